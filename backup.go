@@ -0,0 +1,209 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// A backup is a gzipped tar holding manifest.json followed by the files it
+// lists. The manifest records checksums so a damaged or edited archive is
+// refused on restore.
+
+// backupFormat is written to every manifest so the layout can evolve.
+const backupFormat = 1
+
+const (
+	manifestName   = "manifest.json"
+	backupDataName = "data/todos.json"
+)
+
+type backupManifest struct {
+	Format       int          `json:"format"`
+	StoreVersion int          `json:"storeVersion"`
+	Created      time.Time    `json:"created"`
+	Files        []backupFile `json:"files"`
+}
+
+type backupFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// runBackup handles the backup subcommand.
+func runBackup(args []string) error {
+	if len(args) == 0 {
+		return &UsageError{Err: errors.New("backup needs create or restore")}
+	}
+
+	fs := flag.NewFlagSet("backup "+args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	file := fs.String("file", "", "the list to back up or restore into instead of the default data file")
+	force := fs.Bool("force", false, "restore over data that is newer than the backup")
+	if err := fs.Parse(args[1:]); err != nil {
+		return &UsageError{Err: err}
+	}
+	if fs.NArg() != 1 {
+		return &UsageError{Err: fmt.Errorf("backup %s needs exactly one archive path", args[0])}
+	}
+	path, err := resolveStorePath(*file, nil)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "create":
+		return createBackup(fs.Arg(0), path)
+	case "restore":
+		return restoreBackup(fs.Arg(0), path, *force)
+	}
+	return &UsageError{Err: fmt.Errorf("unknown backup command %q", args[0])}
+}
+
+// createBackup writes the list at dataPath to a new archive.
+func createBackup(archivePath, dataPath string) (err error) {
+	data, err := os.ReadFile(dataPath)
+	if err != nil {
+		return err
+	}
+	if _, err := parseStore(data, dataPath); err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	manifest, err := json.MarshalIndent(backupManifest{
+		Format:       backupFormat,
+		StoreVersion: storeVersion,
+		Created:      time.Now().UTC(),
+		Files:        []backupFile{{Name: backupDataName, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])}},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(archivePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(archivePath)
+		}
+	}()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for _, entry := range []struct {
+		name string
+		data []byte
+	}{{manifestName, manifest}, {backupDataName, data}} {
+		header := &tar.Header{Name: entry.name, Mode: 0o644, Size: int64(len(entry.data)), ModTime: time.Now()}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("backup: %w", err)
+		}
+		if _, err := tw.Write(entry.data); err != nil {
+			return fmt.Errorf("backup: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+	return gz.Close()
+}
+
+// restoreBackup verifies the archive and writes its list to dataPath. Data
+// changed after the backup was made is only replaced with force, and never
+// while lazylist has the list open.
+func restoreBackup(archivePath, dataPath string, force bool) error {
+	manifest, files, err := readBackup(archivePath)
+	if err != nil {
+		return err
+	}
+	data, ok := files[backupDataName]
+	if !ok {
+		return &ValidationError{Operation: "restore", Err: fmt.Errorf("%s has no list", archivePath)}
+	}
+	items, err := parseStore(data, archivePath+":"+backupDataName)
+	if err != nil {
+		return err
+	}
+
+	if pid, alive := lockOwner(dataPath); alive {
+		return &ConflictError{Err: fmt.Errorf("%s is open in lazylist (pid %d); quit it before restoring", dataPath, pid)}
+	}
+	if info, err := os.Stat(dataPath); err == nil && info.ModTime().After(manifest.Created) && !force {
+		return &ConflictError{Err: fmt.Errorf("%s changed after the backup was made; use -force to replace it", dataPath)}
+	}
+	return SaveTodos(dataPath, items)
+}
+
+// readBackup reads the manifest and files of an archive, checking every
+// listed file against its checksum.
+func readBackup(archivePath string) (backupManifest, map[string][]byte, error) {
+	var manifest backupManifest
+	invalid := func(err error) (backupManifest, map[string][]byte, error) {
+		return manifest, nil, &ValidationError{Operation: "restore", Err: fmt.Errorf("%s: %w", archivePath, err)}
+	}
+
+	in, err := os.Open(archivePath)
+	if err != nil {
+		return manifest, nil, err
+	}
+	defer in.Close()
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return invalid(err)
+	}
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return invalid(err)
+		}
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, tr); err != nil {
+			return invalid(err)
+		}
+		files[header.Name] = buf.Bytes()
+	}
+
+	raw, ok := files[manifestName]
+	if !ok {
+		return invalid(errors.New("no manifest"))
+	}
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return invalid(err)
+	}
+	if manifest.Format > backupFormat {
+		return invalid(fmt.Errorf("made by a newer lazylist (format %d)", manifest.Format))
+	}
+	for _, file := range manifest.Files {
+		data, ok := files[file.Name]
+		if !ok {
+			return invalid(fmt.Errorf("%s is missing", file.Name))
+		}
+		sum := sha256.Sum256(data)
+		if int64(len(data)) != file.Size || hex.EncodeToString(sum[:]) != file.SHA256 {
+			return invalid(fmt.Errorf("%s does not match its checksum", file.Name))
+		}
+	}
+	return manifest, files, nil
+}
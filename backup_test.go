@@ -0,0 +1,111 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// setHome points the default data file into a fresh temporary HOME, as if
+// on another machine.
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("LAZYLIST_FILE", "")
+	return home
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	setHome(t)
+	path, err := DefaultStorePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	due := Date{Year: 2030, Month: 5, Day: 17}
+	want := []TodoItem{
+		{Title: "pack", Tags: []string{"trip"}, DueDate: &due},
+		{Title: "book hotel", Completed: true, Priority: PriorityHigh},
+	}
+	if err := SaveTodos(path, want); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(t.TempDir(), "lazylist.tar.gz")
+	if err := runBackup([]string{"create", archive}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	home := setHome(t)
+	if err := runBackup([]string{"restore", archive}); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	restored := filepath.Join(home, ".local", "share", "lazylist", "todos.json")
+	got, err := LoadTodos(restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(itemTitles(got), itemTitles(want)) || *got[0].DueDate != due || got[0].Tags[0] != "trip" ||
+		!got[1].Completed || got[1].Priority != PriorityHigh {
+		t.Errorf("restored %+v, want %+v", got, want)
+	}
+}
+
+func TestBackupRestoreChecksChecksums(t *testing.T) {
+	setHome(t)
+	dir := t.TempDir()
+	list := filepath.Join(dir, "todos.json")
+	if err := SaveTodos(list, []TodoItem{{Title: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(dir, "backup.tar.gz")
+	if err := createBackup(archive, list); err != nil {
+		t.Fatal(err)
+	}
+
+	// Swap in a different list under the same manifest.
+	_, files, err := readBackup(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files[backupDataName] = []byte(`{"version":1,"items":[{"title":"b"}]}`)
+	tampered := filepath.Join(dir, "tampered.tar.gz")
+	writeArchive(t, tampered, files)
+
+	target := filepath.Join(dir, "restored.json")
+	if err := restoreBackup(tampered, target, false); exitCodeFor(err) != ExitValidation {
+		t.Fatalf("restore of a tampered archive: %v, want a validation error", err)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Errorf("tampered restore wrote %s", target)
+	}
+}
+
+// writeArchive writes files to a gzipped tar at path, the manifest first.
+func writeArchive(t *testing.T, path string, files map[string][]byte) {
+	t.Helper()
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for _, name := range []string{manifestName, backupDataName} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name]))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
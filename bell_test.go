@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// reverseVideo is the escape that starts inverted text.
+const reverseVideo = "\x1b[7m"
+
+// TestVisualBellShowsInEveryMode checks that an invalid key inverts the
+// header, which every mode shows, rather than the normal-mode footer.
+func TestVisualBellShowsInEveryMode(t *testing.T) {
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI)
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+
+	tests := []struct {
+		name  string
+		setup func(TodoList) TodoList
+		key   string
+	}{
+		{"normal", func(l TodoList) TodoList { return l }, "Z"},
+		{"input", func(l TodoList) TodoList { return typeText(t, press(t, l, "n"), `\nosuch`) }, "tab"},
+		{"confirm", func(l TodoList) TodoList { return press(t, l, "d") }, "z"},
+		{"paste", func(l TodoList) TodoList {
+			l = press(t, l, "n")
+			l, _ = send(t, l, pasteMsg("a\nb"))
+			return l
+		}, "z"},
+		{"links", func(l TodoList) TodoList {
+			l = press(t, l, "j")
+			_, _ = l.openLinks()
+			return l
+		}, "9"},
+		{"locked", func(l TodoList) TodoList {
+			l.lockPhrase = "secret"
+			l.lock()
+			return typeText(t, l, "wrong")
+		}, "enter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := tt.setup(newTestList(t, "plain", "see https://a.example and https://b.example"))
+			mode := list.currentMode
+			list = press(t, list, tt.key)
+			if list.currentMode != mode {
+				t.Fatalf("mode changed from %v to %v", mode, list.currentMode)
+			}
+			if !list.visualBellOn {
+				t.Fatalf("%q in %v mode did not signal", tt.key, mode)
+			}
+			header := strings.Split(list.View(), "\n")[0]
+			if !strings.Contains(header, reverseVideo) {
+				t.Errorf("header %q is not inverted", header)
+			}
+		})
+	}
+}
+
+func TestEmptyListDeleteSignals(t *testing.T) {
+	list := newTestList(t)
+	list.visualBell = true
+	list, cmd := send(t, list, keyMsg("d"))
+	if !list.visualBellOn || cmd == nil {
+		t.Errorf("visual bell on %v, cmd %v; want the bell and its expiry", list.visualBellOn, cmd)
+	}
+	if list.statusMsg != "nothing to delete" {
+		t.Errorf("status = %q, want %q", list.statusMsg, "nothing to delete")
+	}
+
+	// The audible bell alone still returns the command that rings it.
+	list = newTestList(t)
+	list.visualBell, list.audibleBell = false, true
+	if list, cmd = send(t, list, keyMsg("d")); cmd == nil || list.visualBellOn {
+		t.Errorf("audible bell: cmd %v, visual bell on %v; want only the ring", cmd, list.visualBellOn)
+	}
+}
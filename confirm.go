@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmation is a yes/no question asked before a destructive action.
+// details are shown under the prompt, typically the titles affected. onYes
+// performs the action and may return a command to run after it.
+type confirmation struct {
+	prompt  string
+	details []string
+	onYes   func(t *TodoList) tea.Cmd
+}
+
+// maxConfirmDetails caps the detail lines shown so a large range cannot
+// push the prompt off screen.
+const maxConfirmDetails = 10
+
+func (t *TodoList) askConfirm(c confirmation) {
+	t.confirm = c
+	t.currentMode = ModeConfirm
+}
+
+func (t TodoList) handleConfirmMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		onYes := t.confirm.onYes
+		t.confirm = confirmation{}
+		t.currentMode = ModeNormal
+		return t, onYes(&t)
+
+	case "n", "N", "esc":
+		t.confirm = confirmation{}
+		t.currentMode = ModeNormal
+
+	case "ctrl+c":
+		return t, tea.Quit
+
+	default:
+		return t, t.signalInvalid()
+	}
+	return t, nil
+}
+
+func (t TodoList) confirmLines(width int) []string {
+	lines := []string{truncateToWidth(t.confirm.prompt+" (y/n)", width)}
+	for i, detail := range t.confirm.details {
+		if i == maxConfirmDetails {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("  …and %d more", len(t.confirm.details)-i)))
+			break
+		}
+		lines = append(lines, dimStyle.Render(truncateToWidth("  - "+detail, width)))
+	}
+	return lines
+}
+
+// deleteSelected deletes the selected item, asking first unless
+// confirmation was turned off with -confirm-delete=false. The index is
+// kept in the pending confirmation so a cancelled prompt changes nothing.
+func (t *TodoList) deleteSelected() tea.Cmd {
+	index := t.selectedIndex
+	remove := func(t *TodoList) tea.Cmd {
+		if err := t.DeleteItem(index); err != nil {
+			return t.signalInvalid()
+		}
+		return expireDeleted(t.recentlyDeleted.seq)
+	}
+	if !t.confirmDelete {
+		return remove(t)
+	}
+	t.askConfirm(confirmation{
+		prompt: fmt.Sprintf("delete '%s'?", t.items.at(index).Title),
+		onYes:  remove,
+	})
+	return nil
+}
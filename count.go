@@ -0,0 +1,184 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A count typed before space or d applies it to that many rows starting at
+// the cursor, like vim's 3dd. Counts only exist outside simple mode, where
+// digits pick items instead.
+
+// maxCount bounds the count so a held-down digit cannot overflow it.
+const maxCount = 9999
+
+// addCountDigit extends the pending count. A leading zero is not a count.
+func (t *TodoList) addCountDigit(digit int) bool {
+	if t.count == 0 && digit == 0 {
+		return false
+	}
+	t.count = min(t.count*10+digit, maxCount)
+	return true
+}
+
+// countRange returns the visible rows a count of n covers, starting at the
+// selection and clamped to the end of the list.
+func (t TodoList) countRange(n int) []int {
+	visible := t.visibleItems()
+	start := visiblePos(visible, t.selectedIndex)
+	if start < 0 {
+		return nil
+	}
+	return visible[start:min(start+n, len(visible))]
+}
+
+// ToggleItems toggles every item in indexes as a single undo step.
+func (t *TodoList) ToggleItems(indexes []int) error {
+	if len(indexes) == 0 {
+		return &ValidationError{Operation: "toggle", Err: errors.New("no items")}
+	}
+	for _, index := range indexes {
+		if !t.isValidIndex(index) {
+			return &ValidationError{Operation: "toggle", Err: errors.New("invalid index")}
+		}
+	}
+	indexes = slices.Compact(slices.Sorted(slices.Values(indexes)))
+	t.recordUndo()
+	now := time.Now()
+	t.items.update(indexes, func(item *TodoItem) {
+		item.setCompleted(!item.Completed, now)
+		item.Waiting = false
+	})
+	t.save()
+	return nil
+}
+
+// DeleteItems deletes every item in indexes as a single undo step.
+func (t *TodoList) DeleteItems(indexes []int) error {
+	if len(indexes) == 0 {
+		return &ValidationError{Operation: "delete", Err: errors.New("no items")}
+	}
+	for _, index := range indexes {
+		if !t.isValidIndex(index) {
+			return &ValidationError{Operation: "delete", Err: errors.New("invalid index")}
+		}
+	}
+	indexes = slices.Compact(slices.Sorted(slices.Values(indexes)))
+	t.recordUndo()
+	t.items.remove(indexes)
+	t.selectedIndex = cursorAfterRemoval(t.selectedIndex, indexes)
+	t.adjustCursorAfterDelete()
+	t.save()
+	return nil
+}
+
+// cursorAfterRemoval is where selected ends up once indexes are removed: on
+// the same item if it survives, otherwise on the item that followed it.
+func cursorAfterRemoval(selected int, indexes []int) int {
+	removedBefore := 0
+	for _, index := range indexes {
+		if index < selected {
+			removedBefore++
+		}
+	}
+	return selected - removedBefore
+}
+
+// ClearCompleted removes every completed item as a single undo step and
+// returns how many went.
+func (t *TodoList) ClearCompleted() int {
+	var completed []int
+	for i, item := range t.items.all() {
+		if item.Completed {
+			completed = append(completed, i)
+		}
+	}
+	if t.DeleteItems(completed) != nil {
+		return 0
+	}
+	return len(completed)
+}
+
+// confirmClearCompleted removes the completed items, asking first unless
+// confirmation was turned off with -confirm-delete=false.
+func (t *TodoList) confirmClearCompleted() bool {
+	var titles []string
+	for _, item := range t.items.all() {
+		if item.Completed {
+			titles = append(titles, item.Title)
+		}
+	}
+	if len(titles) == 0 {
+		return false
+	}
+	remove := func(t *TodoList) tea.Cmd {
+		t.statusMsg = fmt.Sprintf("removed %d completed items — press u to undo", t.ClearCompleted())
+		return nil
+	}
+	if !t.confirmDelete {
+		remove(t)
+		return true
+	}
+	t.askConfirm(confirmation{
+		prompt:  fmt.Sprintf("remove %d completed items?", len(titles)),
+		details: titles,
+		onYes:   remove,
+	})
+	return true
+}
+
+// toggleCount toggles count rows from the cursor and leaves the cursor on
+// the last of them.
+func (t *TodoList) toggleCount(count int) bool {
+	indexes := t.countRange(count)
+	if err := t.ToggleItems(indexes); err != nil {
+		return false
+	}
+	t.selectedIndex = indexes[len(indexes)-1]
+
+	done := 0
+	for _, index := range indexes {
+		if t.items.at(index).Completed {
+			done++
+		}
+	}
+	t.statusMsg = fmt.Sprintf("toggled %d items: %d done, %d open", len(indexes), done, len(indexes)-done)
+	return true
+}
+
+// deleteCount deletes count rows from the cursor, asking first with every
+// title that would go unless confirmation was turned off with
+// -confirm-delete=false.
+func (t *TodoList) deleteCount(count int) bool {
+	indexes := t.countRange(count)
+	if len(indexes) == 0 {
+		return false
+	}
+
+	remove := func(t *TodoList) tea.Cmd {
+		if err := t.DeleteItems(indexes); err != nil {
+			t.statusMsg = err.Error()
+			return nil
+		}
+		t.statusMsg = fmt.Sprintf("deleted %d items — press u to undo", len(indexes))
+		return nil
+	}
+	if !t.confirmDelete {
+		remove(t)
+		return true
+	}
+	titles := make([]string, len(indexes))
+	for i, index := range indexes {
+		titles[i] = t.items.at(index).Title
+	}
+	t.askConfirm(confirmation{
+		prompt:  fmt.Sprintf("delete %d items?", len(indexes)),
+		details: titles,
+		onYes:   remove,
+	})
+	return true
+}
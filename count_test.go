@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestDeleteCountAsksFirst(t *testing.T) {
+	list := press(t, newTestList(t, "a", "b", "c", "d"), "j", "2", "d")
+	if list.currentMode != ModeConfirm {
+		t.Fatalf("mode = %v, want ModeConfirm", list.currentMode)
+	}
+	assertTitles(t, list, "a", "b", "c", "d")
+	list = press(t, list, "y")
+	assertTitles(t, list, "a", "d")
+}
+
+func TestDeleteCountWithoutConfirmation(t *testing.T) {
+	list := newTestList(t, "a", "b", "c", "d")
+	list.confirmDelete = false
+	list = press(t, list, "j", "2", "d")
+	if list.currentMode != ModeNormal {
+		t.Fatalf("mode = %v, want ModeNormal", list.currentMode)
+	}
+	assertTitles(t, list, "a", "d")
+	list = press(t, list, "u")
+	assertTitles(t, list, "a", "b", "c", "d")
+}
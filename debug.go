@@ -0,0 +1,16 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateBudget is the longest a single Update may take before the debug
+// build reports it.
+const updateBudget = time.Millisecond
+
+// observeUpdate, when set, is called after every Update with the message and
+// the time it started. It is nil in normal builds; build with -tags debug to
+// enable the latency check in debug_on.go.
+var observeUpdate func(msg tea.Msg, start time.Time)
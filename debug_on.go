@@ -0,0 +1,19 @@
+//go:build debug
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func init() {
+	observeUpdate = func(msg tea.Msg, start time.Time) {
+		if elapsed := time.Since(start); elapsed > updateBudget {
+			fmt.Fprintf(os.Stderr, "slow update: %T took %s (budget %s)\n", msg, elapsed, updateBudget)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxDrafts caps how many abandoned inputs are kept for the session.
+const maxDrafts = 5
+
+// draftOfferDuration is how long the "resume previous draft" hint stays up.
+const draftOfferDuration = 5 * time.Second
+
+// draftKey identifies what an input was for: creating an item, or editing an
+// item. Edits are keyed by the item rather than its index, so a draft follows
+// its item when the list is reordered.
+type draftKey struct {
+	action InputAction
+	item   TodoItem
+}
+
+type draft struct {
+	key   draftKey
+	input InputContext
+}
+
+type draftOfferExpiredMsg struct {
+	seq int
+}
+
+func (in InputContext) draftKey() draftKey {
+	if in.Action == ActionEdit {
+		return draftKey{action: ActionEdit, item: in.editing}
+	}
+	return draftKey{action: ActionCreate}
+}
+
+func (t *TodoList) findDraft(key draftKey) int {
+	return slices.IndexFunc(t.drafts, func(d draft) bool {
+		return d.key.action == key.action && sameItem(d.key.item, key.item)
+	})
+}
+
+// pruneDrafts forgets edit drafts whose item has since been deleted or
+// changed, as they can no longer be resumed.
+func (t *TodoList) pruneDrafts() {
+	t.drafts = slices.DeleteFunc(t.drafts, func(d draft) bool {
+		if d.key.action != ActionEdit {
+			return false
+		}
+		_, ok := t.indexOf(d.key.item)
+		return !ok
+	})
+}
+
+// saveDraft keeps the current input when it is abandoned with changes, so it
+// can be resumed the next time the same action is started.
+func (t *TodoList) saveDraft() {
+	if t.input.Content == t.input.InitialVal {
+		return
+	}
+	t.pruneDrafts()
+	t.dropDraft()
+	t.drafts = append(t.drafts, draft{key: t.input.draftKey(), input: t.input})
+	if len(t.drafts) > maxDrafts {
+		t.drafts = slices.Delete(t.drafts, 0, len(t.drafts)-maxDrafts)
+	}
+}
+
+// dropDraft forgets any draft for the current input.
+func (t *TodoList) dropDraft() {
+	if i := t.findDraft(t.input.draftKey()); i >= 0 {
+		t.drafts = slices.Delete(t.drafts, i, i+1)
+	}
+}
+
+// offerDraft shows the resume hint if the input just started has a draft.
+// The hint dismisses itself after a few seconds or on the first keypress.
+func (t *TodoList) offerDraft() tea.Cmd {
+	t.pruneDrafts()
+	if t.findDraft(t.input.draftKey()) < 0 {
+		return nil
+	}
+	t.draftOfferSeq++
+	t.draftOffered = true
+	seq := t.draftOfferSeq
+	return tea.Tick(draftOfferDuration, func(time.Time) tea.Msg {
+		return draftOfferExpiredMsg{seq: seq}
+	})
+}
+
+// resumeDraft replaces the input with its draft, cursor included.
+func (t *TodoList) resumeDraft() bool {
+	i := t.findDraft(t.input.draftKey())
+	if !t.draftOffered || i < 0 {
+		return false
+	}
+	t.input = t.drafts[i].input
+	t.drafts = slices.Delete(t.drafts, i, i+1)
+	t.draftOffered = false
+	return true
+}
@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+// TestEditDraftFollowsItsItem abandons an edit, moves the item, and checks
+// the draft is offered when that item is edited again and not for the item
+// that took its place.
+func TestEditDraftFollowsItsItem(t *testing.T) {
+	list := newTestList(t, "water plants", "pay rent")
+	list = typeText(t, press(t, list, "j", "e"), " today")
+	list = press(t, list, "esc", "K")
+
+	if list = press(t, list, "j", "e"); list.draftOffered {
+		t.Error("editing the item now in the draft's old place offers the draft")
+	}
+	list = press(t, list, "esc", "k", "e", "up")
+	if list.input.Content != "pay rent today" {
+		t.Errorf("editing the moved item resumes %q, want %q", list.input.Content, "pay rent today")
+	}
+}
+
+func TestEditDraftDroppedWithItsItem(t *testing.T) {
+	list := newTestList(t, "water plants", "pay rent")
+	list.confirmDelete = false
+	list = typeText(t, press(t, list, "e"), " today")
+	list = press(t, list, "esc", "d", "e")
+	if list.draftOffered || len(list.drafts) > 0 {
+		t.Errorf("draft for a deleted item kept: offered %v, drafts %d", list.draftOffered, len(list.drafts))
+	}
+}
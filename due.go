@@ -0,0 +1,269 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dueLayout is the date format of due dates typed into and shown in the
+// input line.
+const dueLayout = "2006-01-02"
+
+// dueSyntax lists the accepted date forms for error messages.
+const dueSyntax = "YYYY-MM-DD, today, tomorrow, a weekday, +Nd or +Nw"
+
+var overdueStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
+
+// Date is a calendar day with no time of day or zone. Due dates are Dates
+// so an item due on the 5th is due on the 5th wherever the list is opened:
+// today and overdue are decided against the local calendar when the list
+// is shown.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// dateOf is the calendar day of t in t's own location.
+func dateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// time is midnight UTC on d, for formatting and day arithmetic; UTC has no
+// daylight saving days to skip or repeat.
+func (d Date) time() time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC)
+}
+
+func (d Date) addDays(n int) Date {
+	return dateOf(d.time().AddDate(0, 0, n))
+}
+
+// daysUntil counts the calendar days from d to other, negative when other
+// comes first.
+func (d Date) daysUntil(other Date) int {
+	return int(other.time().Sub(d.time()) / (24 * time.Hour))
+}
+
+func (d Date) Weekday() time.Weekday {
+	return d.time().Weekday()
+}
+
+func (d Date) Compare(other Date) int {
+	return d.time().Compare(other.time())
+}
+
+func (d Date) Before(other Date) bool {
+	return d.Compare(other) < 0
+}
+
+func (d Date) Format(layout string) string {
+	return d.time().Format(layout)
+}
+
+func (d Date) String() string {
+	return d.Format(dueLayout)
+}
+
+// MarshalText stores the date as YYYY-MM-DD.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText reads YYYY-MM-DD. Files written before due dates were
+// calendar days hold an RFC 3339 timestamp of local midnight where the
+// item was created; the day in that timestamp's own offset is the one
+// that was meant.
+func (d *Date) UnmarshalText(text []byte) error {
+	if parsed, err := time.Parse(dueLayout, string(text)); err == nil {
+		*d = dateOf(parsed)
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, string(text))
+	if err != nil {
+		return fmt.Errorf("due date %q is not YYYY-MM-DD", text)
+	}
+	*d = dateOf(parsed)
+	return nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// parseDueValue reads a due date relative to now's calendar day. A weekday
+// name means the next such day after today.
+func parseDueValue(value string, now time.Time) (Date, bool) {
+	today := dateOf(now)
+	value = strings.ToLower(value)
+	switch value {
+	case "today":
+		return today, true
+	case "tomorrow":
+		return today.addDays(1), true
+	}
+	if day, ok := weekdays[value]; ok {
+		return today.addDays((int(day)-int(today.Weekday())+6)%7 + 1), true
+	}
+	if offset, ok := strings.CutPrefix(value, "+"); ok {
+		if len(offset) < 2 {
+			return Date{}, false
+		}
+		n, err := strconv.Atoi(offset[:len(offset)-1])
+		if err != nil || n < 0 {
+			return Date{}, false
+		}
+		switch offset[len(offset)-1] {
+		case 'd':
+			return today.addDays(n), true
+		case 'w':
+			return today.addDays(7 * n), true
+		}
+		return Date{}, false
+	}
+	parsed, err := time.Parse(dueLayout, value)
+	return dateOf(parsed), err == nil
+}
+
+// parseDue takes the due date out of text, from due:<date> tokens anywhere
+// in it or a trailing @<date>. An invalid date is an error and the text
+// comes back unchanged, except that an @word that does not start like a
+// date is a mention and stays in the title.
+func parseDue(text string, now time.Time) (string, *Date, error) {
+	title, due, err := parseDueFields(text, now)
+	if err != nil {
+		return text, nil, err
+	}
+	title, tokenDue, err := parseDueToken(title, now)
+	if err != nil {
+		return text, nil, err
+	}
+	if tokenDue != nil {
+		due = tokenDue
+	}
+	return title, due, nil
+}
+
+// parseDueFields takes due:<date> tokens out of text; the last one wins.
+func parseDueFields(text string, now time.Time) (string, *Date, error) {
+	var due *Date
+	title, err := takeTokens(text, func(word string) (bool, error) {
+		key, value, ok := strings.Cut(word, ":")
+		if !ok || !strings.EqualFold(key, "due") {
+			return false, nil
+		}
+		date, ok := parseDueValue(value, now)
+		if !ok {
+			return false, &ValidationError{Operation: "due date", Err: fmt.Errorf("%q is not a date; use %s", word, dueSyntax)}
+		}
+		due = &date
+		return true, nil
+	})
+	if err != nil {
+		return text, nil, err
+	}
+	return title, due, nil
+}
+
+// parseDueToken takes a trailing @<date> token off text.
+func parseDueToken(text string, now time.Time) (string, *Date, error) {
+	cut := strings.LastIndexFunc(text, unicode.IsSpace)
+	title, token := strings.TrimSpace(text[:cut+1]), text[cut+1:]
+	word, ok := strings.CutPrefix(token, "@")
+	if !ok || word == "" || title == "" {
+		return text, nil, nil
+	}
+
+	due, ok := parseDueValue(word, now)
+	if !ok {
+		if unicode.IsLetter([]rune(word)[0]) {
+			return text, nil, nil
+		}
+		return text, nil, &ValidationError{Operation: "due date", Err: fmt.Errorf("%q is not a date; use %s", token, dueSyntax)}
+	}
+	return title, &due, nil
+}
+
+// isOverdue reports whether an open item's due date is before now's
+// calendar day.
+func (item TodoItem) isOverdue(now time.Time) bool {
+	if item.Completed || item.DueDate == nil {
+		return false
+	}
+	return item.DueDate.Before(dateOf(now))
+}
+
+// dueLabel renders the due date next to a title, flagging overdue items.
+func (item TodoItem) dueLabel(now time.Time) string {
+	if item.DueDate == nil {
+		return ""
+	}
+	layout := "Jan 2"
+	if item.DueDate.Year != now.Year() {
+		layout = "Jan 2 2006"
+	}
+	date := item.DueDate.Format(layout)
+	if item.isOverdue(now) {
+		return overdueStyle.Render("(overdue " + date + ")")
+	}
+	return dimStyle.Render("(due " + date + ")")
+}
+
+func (t *TodoList) SetDueDate(index int, due Date) error {
+	if !t.isValidIndex(index) {
+		return &ValidationError{Operation: "due date", Err: errors.New("invalid index")}
+	}
+	t.recordUndo()
+	t.items.update([]int{index}, func(item *TodoItem) { item.DueDate = &due })
+	t.save()
+	return nil
+}
+
+// SortByDue orders the list by due date, earliest first, with undated items
+// last. Ties keep their order and the cursor stays on the selected item.
+func (t *TodoList) SortByDue() {
+	t.sortItems(func(a, b TodoItem) int {
+		switch {
+		case a.DueDate == nil && b.DueDate == nil:
+			return 0
+		case a.DueDate == nil:
+			return 1
+		case b.DueDate == nil:
+			return -1
+		}
+		return a.DueDate.Compare(*b.DueDate)
+	})
+}
+
+// sortItems stably sorts the list by compare as one undo step, keeping the
+// cursor on the item it was on.
+func (t *TodoList) sortItems(compare func(a, b TodoItem) int) {
+	items := t.items.slice()
+	order := allIndexes(len(items))
+	slices.SortStableFunc(order, func(i, j int) int {
+		return compare(items[i], items[j])
+	})
+
+	t.recordUndo()
+	sorted := make([]TodoItem, len(order))
+	for i, index := range order {
+		sorted[i] = items[index]
+	}
+	t.items = newItemList(sorted)
+	t.selectedIndex = max(slices.Index(order, t.selectedIndex), 0)
+	t.save()
+}
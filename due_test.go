@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDueDateFollowsLocalCalendarAcrossZones(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	losAngeles := time.FixedZone("PDT", -7*60*60)
+
+	// Set at 08:00 in Tokyo on the 10th, which is still the 9th in Los
+	// Angeles.
+	created := time.Date(2030, 6, 10, 8, 0, 0, 0, tokyo)
+	_, due, err := parseDue("pack due:today", created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(TodoItem{Title: "pack", DueDate: due})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// After the flight it is the afternoon of the 10th in Los Angeles.
+	var item TodoItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		t.Fatal(err)
+	}
+	landed := time.Date(2030, 6, 10, 15, 0, 0, 0, losAngeles)
+	if item.isOverdue(landed) {
+		t.Errorf("item due today (%v) is overdue at %v", item.DueDate, landed)
+	}
+	if next := landed.AddDate(0, 0, 1); !item.isOverdue(next) {
+		t.Errorf("item due %v is not overdue at %v", item.DueDate, next)
+	}
+}
+
+func TestDueDateStoredAsCalendarDay(t *testing.T) {
+	due := Date{Year: 2030, Month: time.May, Day: 17}
+	data, err := json.Marshal(TodoItem{Title: "x", DueDate: &due})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"due":"2030-05-17"`; !strings.Contains(string(data), want) {
+		t.Errorf("marshalled %s, want it to hold %s", data, want)
+	}
+}
+
+func TestDueDateReadsOldTimestamps(t *testing.T) {
+	// Written by an earlier version in UTC+9: local midnight on the 17th.
+	var item TodoItem
+	if err := json.Unmarshal([]byte(`{"title":"x","due":"2030-05-17T00:00:00+09:00"}`), &item); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Date{Year: 2030, Month: time.May, Day: 17}); *item.DueDate != want {
+		t.Errorf("due = %v, want %v", *item.DueDate, want)
+	}
+}
+
+func TestDueDateOnDaylightSavingDays(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	tests := []struct {
+		name  string
+		now   time.Time
+		value string
+		want  Date
+	}{
+		// The 23-hour day clocks go forward.
+		{"spring tomorrow", time.Date(2030, 3, 10, 23, 30, 0, 0, newYork), "tomorrow", Date{2030, time.March, 11}},
+		{"spring +1d", time.Date(2030, 3, 10, 0, 30, 0, 0, newYork), "+1d", Date{2030, time.March, 11}},
+		// The 25-hour day clocks go back.
+		{"autumn today", time.Date(2030, 11, 3, 23, 30, 0, 0, newYork), "today", Date{2030, time.November, 3}},
+		{"autumn +1w", time.Date(2030, 11, 3, 1, 30, 0, 0, newYork), "+1w", Date{2030, time.November, 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseDueValue(tt.value, tt.now)
+			if !ok || got != tt.want {
+				t.Fatalf("parseDueValue(%q) = %v, %v; want %v", tt.value, got, ok, tt.want)
+			}
+			item := TodoItem{DueDate: &got}
+			if item.isOverdue(tt.now) {
+				t.Errorf("due %v is overdue at %v", got, tt.now)
+			}
+		})
+	}
+}
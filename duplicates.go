@@ -0,0 +1,86 @@
+package main
+
+import "strings"
+
+// Adding an item whose title matches a completed one warns that it looks
+// like a duplicate. The check runs on every key typed into the input, so
+// rather than walking the list each time it keeps an index of the
+// completed titles per chunk of the list, rebuilt only for chunks that
+// changed.
+
+// completedIndex maps the chunks of a list to the completed titles in
+// them. It is shared by every copy of the list, which is why TodoList
+// holds it behind a pointer.
+type completedIndex struct {
+	items  itemList
+	chunks map[*TodoItem]chunkTitles
+}
+
+// chunkTitles holds the offsets of the completed items in one chunk, by
+// duplicateKey of their title.
+type chunkTitles struct {
+	length int
+	titles map[string][]int
+}
+
+// duplicateKey is the form titles are compared in: trimmed and without
+// case.
+func duplicateKey(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
+
+// completedDuplicate reports whether a completed item other than skip has
+// title.
+func (t *TodoList) completedDuplicate(title string, skip int) bool {
+	key := duplicateKey(title)
+	if t.completed == nil {
+		for i, item := range t.items.all() {
+			if i != skip && item.Completed && duplicateKey(item.Title) == key {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.completed.refresh(t.items)
+	for c, chunk := range t.items.chunks {
+		for _, offset := range t.completed.chunks[&chunk[0]].titles[key] {
+			if t.items.starts[c]+offset != skip {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// refresh points the index at items, indexing the chunks it has not seen
+// and forgetting the ones items no longer holds.
+func (x *completedIndex) refresh(items itemList) {
+	if x.chunks != nil && x.items.same(items) {
+		return
+	}
+	chunks := make(map[*TodoItem]chunkTitles, len(items.chunks))
+	for _, chunk := range items.chunks {
+		indexed, ok := x.chunks[&chunk[0]]
+		if !ok || indexed.length != len(chunk) {
+			indexed = indexChunk(chunk)
+		}
+		chunks[&chunk[0]] = indexed
+	}
+	x.items, x.chunks = items, chunks
+}
+
+func indexChunk(chunk []TodoItem) chunkTitles {
+	indexed := chunkTitles{length: len(chunk)}
+	for i, item := range chunk {
+		if !item.Completed {
+			continue
+		}
+		if indexed.titles == nil {
+			indexed.titles = make(map[string][]int)
+		}
+		key := duplicateKey(item.Title)
+		indexed.titles[key] = append(indexed.titles[key], i)
+	}
+	return indexed
+}
@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestEditFollowsItemWhenListShrinks(t *testing.T) {
+	list := press(t, newTestList(t, "a", "b", "c"), "G", "e")
+	if err := list.DeleteItem(0); err != nil {
+		t.Fatal(err)
+	}
+	list = typeText(t, list, " edited")
+	list = press(t, list, "enter")
+	assertTitles(t, list, "b", "c edited")
+}
+
+func TestEditOfRemovedItemChangesNothing(t *testing.T) {
+	list := press(t, newTestList(t, "a", "b", "c"), "G", "e")
+	if err := list.DeleteItem(2); err != nil {
+		t.Fatal(err)
+	}
+	list.items.append(TodoItem{Title: "d"})
+	list = typeText(t, list, " edited")
+	list = press(t, list, "enter")
+
+	assertTitles(t, list, "a", "b", "d")
+	if list.lastErr == nil {
+		t.Error("no error reported for the vanished item")
+	}
+	if list.currentMode != ModeNormal {
+		t.Errorf("mode = %v, want ModeNormal", list.currentMode)
+	}
+}
+
+func TestToggleAfterListShrinks(t *testing.T) {
+	list := press(t, newTestList(t, "a", "b", "c"), "G")
+	// Shrink the list behind the cursor's back, as a reload would.
+	list.items.remove([]int{1, 2})
+	list = press(t, list, " ")
+	assertTitles(t, list, "a")
+	if item, _ := list.At(0); item.Completed {
+		t.Error("toggled an item the cursor was not on")
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ExitCode is the process status for a command. Every non-interactive path
+// exits through exitWithError so scripts can rely on these values.
+type ExitCode int
+
+const (
+	ExitOK ExitCode = iota
+	ExitEmpty
+	ExitUsage
+	ExitValidation
+	ExitIO
+	ExitConflict
+)
+
+var exitCodeNames = map[ExitCode]string{
+	ExitOK:         "ok",
+	ExitEmpty:      "empty",
+	ExitUsage:      "usage",
+	ExitValidation: "validation",
+	ExitIO:         "io",
+	ExitConflict:   "conflict",
+}
+
+var exitCodeDocs = []struct {
+	code ExitCode
+	doc  string
+}{
+	{ExitOK, "success"},
+	{ExitEmpty, "empty result: nothing matched or nothing to do"},
+	{ExitUsage, "usage error: bad flags or arguments"},
+	{ExitValidation, "validation error: input was read but is not valid"},
+	{ExitIO, "I/O or lock error"},
+	{ExitConflict, "conflict: ambiguous match, merge conflict or newer data in the way"},
+}
+
+// UsageError reports a command line that could not be understood.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// ErrEmpty is wrapped by errors for a command that ran but had nothing to
+// show.
+var ErrEmpty = errors.New("empty result")
+
+// ConflictError reports an operation refused because it would clash with
+// existing state, such as overwriting newer data.
+type ConflictError struct {
+	Err error
+}
+
+func (e *ConflictError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// exitCodeFor maps an error onto its exit code. Anything not classified
+// otherwise came from the filesystem or terminal and counts as I/O.
+func exitCodeFor(err error) ExitCode {
+	var usageErr *UsageError
+	var validationErr *ValidationError
+	var conflictErr *ConflictError
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrEmpty):
+		return ExitEmpty
+	case errors.As(err, &usageErr):
+		return ExitUsage
+	case errors.As(err, &validationErr):
+		return ExitValidation
+	case errors.As(err, &conflictErr):
+		return ExitConflict
+	}
+	return ExitIO
+}
+
+// exitWithError prints err on stderr with a machine-parsable
+// "lazylist: <kind>: " prefix and exits with the matching code.
+func exitWithError(err error) {
+	code := exitCodeFor(err)
+	fmt.Fprintf(os.Stderr, "lazylist: %s: %v\n", exitCodeNames[code], err)
+	os.Exit(int(code))
+}
+
+// printUsage is the help -h prints, listing the exit codes after the flags.
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: lazylist [flags] [file]\n")
+	fmt.Fprintf(w, "       lazylist backup create [-file path] archive.tar.gz\n")
+	fmt.Fprintf(w, "       lazylist backup restore [-force] [-file path] archive.tar.gz\n")
+	fmt.Fprintf(w, "       lazylist export [-format md] [-file path]\n\nFlags:\n")
+	flag.CommandLine.SetOutput(w)
+	flag.PrintDefaults()
+	fmt.Fprintf(w, "\nExit codes:\n")
+	for _, entry := range exitCodeDocs {
+		fmt.Fprintf(w, "  %d  %-10s  %s\n", entry.code, exitCodeNames[entry.code], entry.doc)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestExitCodes runs each command's failure modes and checks the exit code
+// its error maps onto.
+func TestExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	list := filepath.Join(dir, "todos.json")
+	if err := SaveTodos(list, []TodoItem{{Title: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(dir, "backup.tar.gz")
+	if err := createBackup(archive, list); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	corrupt := write("corrupt.json", "{")
+	notArchive := write("not-archive.tar.gz", "plain text")
+	badMode := write("bad-mode.json", `{"mode": "nosuch"}`)
+	empty := write("empty.json", `{"version": 1, "items": []}`)
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		name string
+		run  func() error
+		want ExitCode
+	}{
+		{"backup without command", func() error { return runBackup(nil) }, ExitUsage},
+		{"backup unknown command", func() error { return runBackup([]string{"frob", archive}) }, ExitUsage},
+		{"backup unknown flag", func() error { return runBackup([]string{"create", "-nosuch", archive}) }, ExitUsage},
+		{"backup two archives", func() error { return runBackup([]string{"create", "-file", list, archive, archive}) }, ExitUsage},
+		{"backup create", func() error {
+			return runBackup([]string{"create", "-file", list, filepath.Join(dir, "again.tar.gz")})
+		}, ExitOK},
+		{"restore missing archive", func() error { return restoreBackup(missing, list, false) }, ExitIO},
+		{"restore damaged archive", func() error { return restoreBackup(notArchive, list, false) }, ExitValidation},
+		{"restore over newer data", func() error {
+			future := time.Now().Add(time.Hour)
+			if err := os.Chtimes(list, future, future); err != nil {
+				t.Fatal(err)
+			}
+			return restoreBackup(archive, list, false)
+		}, ExitConflict},
+		{"restore over newer data with force", func() error { return restoreBackup(archive, list, true) }, ExitOK},
+		{"restore over open list", func() error {
+			release, err := acquireLock(list)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer release()
+			return restoreBackup(archive, list, true)
+		}, ExitConflict},
+		{"export unknown format", func() error { return runExport([]string{"-format", "html"}) }, ExitUsage},
+		{"export with argument", func() error { return runExport([]string{"out.md"}) }, ExitUsage},
+		{"export corrupt list", func() error { return runExport([]string{"-file", corrupt}) }, ExitValidation},
+		{"export empty list", func() error { return runExport([]string{"-file", empty}) }, ExitEmpty},
+		{"two files", func() error { _, err := resolveStorePath("", []string{"a", "b"}); return err }, ExitUsage},
+		{"file flag and argument differ", func() error { _, err := resolveStorePath("a", []string{"b"}); return err }, ExitUsage},
+		{"list is a directory", func() error { return ensureStoreFile(dir) }, ExitIO},
+		{"add empty title", func() error { _, err := addFromCLI(list, "  "); return err }, ExitValidation},
+		{"add to corrupt list", func() error { _, err := addFromCLI(corrupt, "x"); return err }, ExitValidation},
+		{"add in missing directory", func() error {
+			_, err := addFromCLI(filepath.Join(missing, "todos.json"), "x")
+			return err
+		}, ExitIO},
+		{"render missing fixture", func() error { return renderFrame(missing, io.Discard) }, ExitIO},
+		{"render unknown mode", func() error { return renderFrame(badMode, io.Discard) }, ExitValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if got := exitCodeFor(err); got != tt.want {
+				t.Errorf("exit code %d (%s) for %v, want %d (%s)", got, exitCodeNames[got], err, tt.want, exitCodeNames[tt.want])
+			}
+		})
+	}
+}
+
+func TestUsageListsExitCodes(t *testing.T) {
+	var usage strings.Builder
+	printUsage(&usage)
+	flag.CommandLine.SetOutput(nil)
+	for _, entry := range exitCodeDocs {
+		if line := fmt.Sprintf("  %d  %-10s  %s", entry.code, exitCodeNames[entry.code], entry.doc); !strings.Contains(usage.String(), line) {
+			t.Errorf("usage is missing %q", line)
+		}
+	}
+}
+
+// TestMain runs main itself when asked to by TestFlagErrorExitCode, so the
+// top-level flag handling can be checked in a child process.
+func TestMain(m *testing.M) {
+	if args, ok := os.LookupEnv("LAZYLIST_TEST_MAIN_ARGS"); ok {
+		os.Args = append([]string{"lazylist"}, strings.Fields(args)...)
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestFlagErrorExitCode(t *testing.T) {
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), "LAZYLIST_TEST_MAIN_ARGS=-nosuch")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != int(ExitUsage) {
+		t.Fatalf("lazylist -nosuch: %v, want exit code %d", err, ExitUsage)
+	}
+	if want := "lazylist: usage: flag provided but not defined: -nosuch\n"; stderr.String() != want {
+		t.Errorf("stderr = %q, want %q", stderr.String(), want)
+	}
+}
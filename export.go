@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The list exports as a GitHub-style Markdown checklist, one item per line
+// in list order. Item properties are written in the syntax the input
+// accepts, so an exported line typed back in makes the same item.
+
+// ExportMarkdown writes every item as a "- [ ]" or "- [x]" line. An empty
+// list writes nothing.
+func (t *TodoList) ExportMarkdown(w io.Writer) error {
+	return t.items.writeMarkdown(w)
+}
+
+// writeMarkdown is ExportMarkdown for a snapshot of the items.
+func (l itemList) writeMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, item := range l.all() {
+		checked := " "
+		if item.Completed {
+			checked = "x"
+		}
+		fmt.Fprintf(bw, "- [%s] %s\n", checked, item.markdown())
+	}
+	return bw.Flush()
+}
+
+// markdown is the item's priority prefix and title followed by its due
+// date, fields, tags and waiting flag.
+func (item TodoItem) markdown() string {
+	var words []string
+	switch item.Priority {
+	case PriorityHigh:
+		words = append(words, "!!")
+	case PriorityMedium:
+		words = append(words, "!")
+	case PriorityLow:
+		words = append(words, "!-")
+	}
+	words = append(words, item.Title)
+	if item.DueDate != nil {
+		words = append(words, "due:"+item.DueDate.Format(dueLayout))
+	}
+	if len(item.Fields) > 0 {
+		words = append(words, formatFields(item.Fields))
+	}
+	if len(item.Tags) > 0 {
+		words = append(words, formatTags(item.Tags))
+	}
+	if item.Waiting {
+		words = append(words, waitingTag)
+	}
+	return strings.Join(words, " ")
+}
+
+// exportPath is where the x key writes the export: the data file's name
+// with a .md extension, in the same directory.
+func exportPath(dataPath string) string {
+	return strings.TrimSuffix(dataPath, filepath.Ext(dataPath)) + ".md"
+}
+
+type exportedMsg struct {
+	path string
+}
+
+// exportCmd writes a snapshot of the items to path in the background. A
+// failure comes back as an error for the status line.
+func (t *TodoList) exportCmd(path string) tea.Cmd {
+	// Like saveCmd, the snapshot shares its chunks with later versions of
+	// the list and is only read in the background.
+	items := t.items
+	return func() tea.Msg {
+		var sb strings.Builder
+		if err := items.writeMarkdown(&sb); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(sb.String()), 0o644); err != nil {
+			return fmt.Errorf("export: %w", err)
+		}
+		return exportedMsg{path: path}
+	}
+}
+
+// runExport handles the export subcommand, printing the list to stdout.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	format := fs.String("format", "md", "the export format; only md is supported")
+	file := fs.String("file", "", "the list to export instead of the default data file")
+	if err := fs.Parse(args); err != nil {
+		return &UsageError{Err: err}
+	}
+	if fs.NArg() != 0 {
+		return &UsageError{Err: errors.New("export takes no arguments; it writes to stdout")}
+	}
+	if *format != "md" {
+		return &UsageError{Err: fmt.Errorf("unknown export format %q; use md", *format)}
+	}
+	path, err := resolveStorePath(*file, nil)
+	if err != nil {
+		return err
+	}
+
+	items, err := LoadTodos(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("%s has no items to export: %w", path, ErrEmpty)
+	}
+	return NewTodoList(items).ExportMarkdown(os.Stdout)
+}
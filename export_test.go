@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestExportLineTypesBackIn types each exported line into the input and
+// checks the item it makes matches the one exported.
+func TestExportLineTypesBackIn(t *testing.T) {
+	due := Date{Year: 2030, Month: time.May, Day: 17}
+	items := []TodoItem{
+		{Title: "plain"},
+		{Title: "urgent", Priority: PriorityHigh},
+		{Title: "soon", Priority: PriorityMedium, DueDate: &due},
+		{Title: "someday", Priority: PriorityLow},
+		{Title: "call bob", Fields: map[string]string{"phone": "555"}, Tags: []string{"home"}, Waiting: true},
+	}
+	var out strings.Builder
+	if err := NewTodoList(items).ExportMarkdown(&out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(items) {
+		t.Fatalf("exported %d lines, want %d:\n%s", len(lines), len(items), out.String())
+	}
+
+	for i, line := range lines {
+		typed, ok := strings.CutPrefix(line, "- [ ] ")
+		if !ok {
+			t.Fatalf("line %q is not an open checklist item", line)
+		}
+		list := typeText(t, press(t, newTestList(t), "n"), typed)
+		list = press(t, list, "enter")
+		if list.lastErr != nil {
+			t.Fatalf("typing %q: %v", typed, list.lastErr)
+		}
+		got, _ := list.At(0)
+		if got.Priority != items[i].Priority {
+			t.Errorf("typing %q made priority %v, want %v", typed, got.Priority, items[i].Priority)
+		}
+		if got.markdown() != items[i].markdown() {
+			t.Errorf("typing %q made %q, want %q", typed, got.markdown(), items[i].markdown())
+		}
+	}
+}
+
+// TestExportWritesSnapshot edits the list after pressing x and before the
+// export runs; the file must hold the list as it was when x was pressed.
+func TestExportWritesSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todos.json")
+	list := newTestList(t, "water plants", "pay rent")
+	list.store = NewStore(path)
+
+	list, cmd := send(t, list, keyMsg("x"))
+	list = press(t, list, " ")
+	if msg := cmd(); msg != (exportedMsg{path: exportPath(path)}) {
+		t.Fatalf("export returned %v", msg)
+	}
+	data, err := os.ReadFile(exportPath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "- [ ] water plants\n- [ ] pay rent\n"; string(data) != want {
+		t.Errorf("exported %q, want %q", data, want)
+	}
+	if item, _ := list.At(0); !item.Completed {
+		t.Error("space after x did not complete the item")
+	}
+}
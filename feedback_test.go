@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// normalModeKeys are the keys handleNormalMode binds, plus a count digit
+// and an unbound key.
+var normalModeKeys = []string{
+	"q", "esc", "ctrl+c", "up", "k", "down", "j", "pgdown", "pgup", "ctrl+d", "ctrl+u",
+	"g", "home", "G", "end", "shift+up", "K", "shift+down", "J", "/", "f", "i", "x", "t",
+	"a", "enter", " ", "n", "ctrl+p", "s", "p", "C", "o", "w", "P", "m", "e", "d", "u",
+	"ctrl+r", "3", "Z",
+}
+
+// listState is what a key can visibly change, apart from the status line.
+func listState(list TodoList) string {
+	return fmt.Sprintf("%+v|%d|%v|%v|%q|%q|%v|%v|%d",
+		list.Items(), list.selectedIndex, list.currentMode, list.filterMode, list.filter,
+		list.tagFilter, list.showDetails, list.simpleMode, list.count)
+}
+
+// TestEveryNormalKeyGivesFeedback presses each normal-mode key on lists
+// where it may have nothing to act on, and checks that it either changed
+// something or said why not.
+func TestEveryNormalKeyGivesFeedback(t *testing.T) {
+	lists := map[string][]string{
+		"empty": nil,
+		"one":   {"only"},
+		"three": {"a", "b", "c"},
+	}
+	for name, items := range lists {
+		for _, key := range normalModeKeys {
+			t.Run(fmt.Sprintf("%s/%q", name, key), func(t *testing.T) {
+				list := newTestList(t, items...)
+				before := listState(list)
+				list, cmd := send(t, list, keyMsg(key))
+				if listState(list) == before && list.statusMsg == "" && list.lastErr == nil && cmd == nil {
+					t.Errorf("%q did nothing and said nothing", key)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// reservedFieldKeys are field names claimed by built-in item properties.
+var reservedFieldKeys = []string{"due", "priority"}
+
+// fieldToken matches a key:value token. Values starting with // are left in
+// the title so URLs are not mistaken for fields.
+var fieldToken = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*):([^/\s]\S*)$`)
+
+// parseFields pulls key:value tokens out of text, returning the remaining
+// title and the fields found.
+func parseFields(text string) (string, map[string]string, error) {
+	var fields map[string]string
+	title, err := takeTokens(text, func(word string) (bool, error) {
+		match := fieldToken.FindStringSubmatch(word)
+		if match == nil {
+			return false, nil
+		}
+
+		key := strings.ToLower(match[1])
+		// due: tokens left by parseDue failed to parse; keep them in the
+		// title where titleWarnings points them out.
+		if key == "due" {
+			return false, nil
+		}
+		if slices.Contains(reservedFieldKeys, key) {
+			return false, &ValidationError{Operation: "fields", Err: fmt.Errorf("%q is reserved and cannot be used as a custom field", key)}
+		}
+		if fields == nil {
+			fields = make(map[string]string)
+		}
+		fields[key] = match[2]
+		return true, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return title, fields, nil
+}
+
+// formatFields renders fields as space-separated key:value tokens in key
+// order, the same syntax parseFields reads.
+func formatFields(fields map[string]string) string {
+	tokens := make([]string, 0, len(fields))
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		tokens = append(tokens, key+":"+fields[key])
+	}
+	return strings.Join(tokens, " ")
+}
+
+// parseFieldQuery reads a field:key=value search query.
+func parseFieldQuery(query string) (key, value string, ok bool) {
+	rest, found := strings.CutPrefix(query, "field:")
+	if !found {
+		return "", "", false
+	}
+	key, value, ok = strings.Cut(rest, "=")
+	return strings.ToLower(key), value, ok && key != ""
+}
+
+// hasField reports whether the item's key field is value, ignoring case, as
+// a field:key=value query matches it.
+func (item *TodoItem) hasField(key, value string) bool {
+	field, found := item.Fields[key]
+	return found && strings.EqualFold(field, value)
+}
+
+func (t *TodoList) SetField(index int, key, value string) error {
+	if !t.isValidIndex(index) {
+		return &ValidationError{Operation: "fields", Err: errors.New("invalid index")}
+	}
+	key = strings.ToLower(key)
+	if slices.Contains(reservedFieldKeys, key) {
+		return &ValidationError{Operation: "fields", Err: fmt.Errorf("%q is reserved and cannot be used as a custom field", key)}
+	}
+	t.recordUndo()
+	t.items.update([]int{index}, func(item *TodoItem) {
+		// The map is shared with undo history, so change a copy.
+		fields := maps.Clone(item.Fields)
+		if fields == nil {
+			fields = make(map[string]string)
+		}
+		fields[key] = value
+		item.Fields = fields
+	})
+	t.save()
+	return nil
+}
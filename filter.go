@@ -0,0 +1,219 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A filter hides items whose title does not contain the query, or whose
+// field does not match a field:key=value query, and the filter mode hides
+// items by completion. The cursor keeps indexing the full
+// items slice, so toggle, edit and delete work on the right item unchanged;
+// only movement and rendering walk the visible subset, and the selection is
+// kept on a visible item.
+
+type FilterMode int
+
+const (
+	FilterAll FilterMode = iota
+	FilterActive
+	FilterCompleted
+	FilterWaiting
+)
+
+var filterModeNames = map[FilterMode]string{
+	FilterAll:       "all",
+	FilterActive:    "active",
+	FilterCompleted: "completed",
+	FilterWaiting:   "waiting",
+}
+
+func (f FilterMode) String() string {
+	return filterModeNames[f]
+}
+
+// next cycles all, active, completed, waiting and back to all.
+func (f FilterMode) next() FilterMode {
+	return (f + 1) % (FilterWaiting + 1)
+}
+
+// shows reports whether an item passes the filter mode. Waiting items are
+// not actionable, so only the waiting view shows them among open items.
+func (f FilterMode) shows(item *TodoItem) bool {
+	switch f {
+	case FilterActive:
+		return !item.Completed && !item.Waiting
+	case FilterCompleted:
+		return item.Completed
+	case FilterWaiting:
+		return item.Waiting
+	}
+	return true
+}
+
+func (t *TodoList) enterSearchMode() {
+	t.currentMode = ModeSearch
+	t.input = InputContext{
+		Content:    t.filter,
+		InitialVal: t.filter,
+		Cursor:     utf8.RuneCountInString(t.filter),
+	}
+}
+
+func (t *TodoList) exitSearchMode() {
+	t.currentMode = ModeNormal
+	t.input = InputContext{}
+}
+
+func (t TodoList) handleSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return t, tea.Quit
+
+	case "esc":
+		t.filter = ""
+		t.exitSearchMode()
+
+	case "enter":
+		t.filter = strings.TrimSpace(t.input.Content)
+		t.exitSearchMode()
+
+	default:
+		t.editInput(msg)
+	}
+	return t, nil
+}
+
+// filterQuery is the filter in effect: the text being typed while filtering,
+// otherwise the locked filter.
+func (t TodoList) filterQuery() string {
+	if t.currentMode == ModeSearch {
+		return strings.TrimSpace(t.input.Content)
+	}
+	return t.filter
+}
+
+// visibleCache holds the last visibleItems result and what it was computed
+// from. Rendering, movement and the selection checks all ask for the visible
+// rows several times per key, and with a long list rescanning it each time
+// is most of the cost of a key press.
+type visibleCache struct {
+	items   itemList
+	query   string
+	mode    FilterMode
+	tag     string
+	visible []int
+}
+
+// visibleItems returns the indexes into items that pass the filter, the
+// filter mode and the tag filter, in list order. The result is shared and
+// must not be modified.
+func (t TodoList) visibleItems() []int {
+	query := strings.ToLower(t.filterQuery())
+	cache := t.visible
+	if cache == nil {
+		return t.scanVisible(query, nil)
+	}
+	if cache.query != query || cache.mode != t.filterMode || cache.tag != t.tagFilter {
+		cache.visible = nil
+	}
+	if cache.visible == nil || !cache.items.same(t.items) {
+		*cache = visibleCache{
+			items:   t.items,
+			query:   query,
+			mode:    t.filterMode,
+			tag:     t.tagFilter,
+			visible: t.scanVisible(query, cache),
+		}
+	}
+	return cache.visible
+}
+
+// scanVisible walks the list for the items visibleItems returns. Chunks
+// unchanged since prev, which was computed with the same filters, take
+// their rows from prev instead of being walked again, so a change costs
+// the chunks it touched.
+func (t TodoList) scanVisible(query string, prev *visibleCache) []int {
+	var reuse map[*TodoItem]int
+	size := t.items.Len()
+	if prev != nil && prev.visible != nil {
+		reuse = make(map[*TodoItem]int, len(prev.items.chunks))
+		for c, chunk := range prev.items.chunks {
+			reuse[&chunk[0]] = c
+		}
+		size = min(size, len(prev.visible)+chunkSize)
+	}
+
+	visible := make([]int, 0, size)
+	for c, chunk := range t.items.chunks {
+		start := t.items.starts[c]
+		if old, ok := reuse[&chunk[0]]; ok && sameChunk(chunk, prev.items.chunks[old]) {
+			oldStart := prev.items.starts[old]
+			lo, _ := slices.BinarySearch(prev.visible, oldStart)
+			hi, _ := slices.BinarySearch(prev.visible, oldStart+len(chunk))
+			if oldStart == start {
+				visible = append(visible, prev.visible[lo:hi]...)
+				continue
+			}
+			for _, index := range prev.visible[lo:hi] {
+				visible = append(visible, index-oldStart+start)
+			}
+			continue
+		}
+		for i := range chunk {
+			if t.shows(&chunk[i], query) {
+				visible = append(visible, start+i)
+			}
+		}
+	}
+	return visible
+}
+
+// shows reports whether item passes the filter mode, the tag filter and
+// query, the lower-cased filter text.
+func (t TodoList) shows(item *TodoItem, query string) bool {
+	if !t.filterMode.shows(item) {
+		return false
+	}
+	if t.tagFilter != "" && !slices.Contains(item.Tags, t.tagFilter) {
+		return false
+	}
+	if key, value, ok := parseFieldQuery(query); ok {
+		return item.hasField(key, value)
+	}
+	return query == "" || strings.Contains(strings.ToLower(item.Title), query)
+}
+
+// visiblePos returns the row of index among visible, or -1 when it is
+// hidden. visible is in list order, so this is a binary search.
+func visiblePos(visible []int, index int) int {
+	if pos, found := slices.BinarySearch(visible, index); found {
+		return pos
+	}
+	return -1
+}
+
+// filtered reports whether anything is hidden from the list.
+func (t TodoList) filtered() bool {
+	return t.filterQuery() != "" || t.filterMode != FilterAll || t.tagFilter != ""
+}
+
+// keepSelectionVisible moves the cursor to the nearest visible item when the
+// filter or an edit has hidden the selected one.
+func (t *TodoList) keepSelectionVisible() {
+	visible := t.visibleItems()
+	if len(visible) == 0 || visiblePos(visible, t.selectedIndex) >= 0 {
+		return
+	}
+	pos, _ := slices.BinarySearch(visible, t.selectedIndex)
+	t.selectedIndex = visible[min(pos, len(visible)-1)]
+}
+
+// hasSelection reports whether the cursor is on an item that can be acted
+// on, which is false when the list is empty or the filter hides everything.
+func (t TodoList) hasSelection() bool {
+	return visiblePos(t.visibleItems(), t.selectedIndex) >= 0
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// isQuit reports whether cmd quits the program.
+func isQuit(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+func TestEscClearsEveryFilterBeforeQuitting(t *testing.T) {
+	list := press(t, newTestList(t, "open", "done"), "j", " ", "f")
+	if list.filterMode != FilterActive {
+		t.Fatalf("filter mode = %v, want active", list.filterMode)
+	}
+	list = typeText(t, press(t, list, "/"), "o")
+	list = press(t, list, "enter")
+
+	list, cmd := send(t, list, keyMsg("esc"))
+	if isQuit(cmd) {
+		t.Fatal("esc quit with filters showing")
+	}
+	if list.filtered() {
+		t.Errorf("after esc: filter %q, mode %v, tag %q; want none", list.filter, list.filterMode, list.tagFilter)
+	}
+	if _, cmd = send(t, list, keyMsg("esc")); !isQuit(cmd) {
+		t.Error("second esc did not quit")
+	}
+}
+
+func TestPaletteKeepsFilters(t *testing.T) {
+	list := press(t, newTestList(t, "buy milk", "buy bread", "sell car"), "j", " ", "f")
+	if list.filterMode != FilterActive {
+		t.Fatalf("filter mode = %v, want active", list.filterMode)
+	}
+
+	list = typeText(t, press(t, list, "ctrl+p"), "buy")
+	matches := list.paletteMatches()
+	if len(matches) != 1 || matches[0].index != 0 {
+		t.Fatalf("matches = %v, want only the visible item 0", matches)
+	}
+	list = press(t, list, "enter")
+	if list.filterMode != FilterActive {
+		t.Errorf("filter mode = %v after the jump, want it kept", list.filterMode)
+	}
+	if list.selectedIndex != 0 || !list.hasSelection() {
+		t.Errorf("selected %d, visible %v", list.selectedIndex, list.visibleItems())
+	}
+}
+
+func TestPaletteRanksAndSelects(t *testing.T) {
+	list := newTestList(t, "read the manual", "groceries", "renew domain", "rd")
+	list = typeText(t, press(t, list, "ctrl+p"), "rd")
+
+	// A consecutive run ranks above letters at word starts, which rank
+	// above letters inside a word.
+	var got []string
+	for _, m := range list.paletteMatches() {
+		got = append(got, list.items.at(m.index).Title)
+	}
+	want := []string{"rd", "renew domain", "read the manual"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ranked %q, want %q", got, want)
+	}
+
+	list = press(t, list, "down", "enter")
+	if list.currentMode != ModeNormal || list.selectedIndex != 2 {
+		t.Errorf("mode %v, cursor on %d; want normal mode on renew domain", list.currentMode, list.selectedIndex)
+	}
+	assertTitles(t, list, "read the manual", "groceries", "renew domain", "rd")
+}
+
+func TestToggleLastVisibleKeepsCursorValid(t *testing.T) {
+	list := press(t, newTestList(t, "a", "b", "c"), "f")
+	if list.filterMode != FilterActive {
+		t.Fatalf("filter mode = %v, want active", list.filterMode)
+	}
+
+	// Completing the last visible row hides it; the cursor moves up.
+	list = press(t, list, "G", " ")
+	if !list.hasSelection() || list.selectedIndex != 1 {
+		t.Fatalf("cursor on %d, visible %v; want 1", list.selectedIndex, list.visibleItems())
+	}
+
+	// Completing the rest leaves nothing to select, and no key panics.
+	list = press(t, list, " ", " ")
+	if len(list.visibleItems()) != 0 || list.hasSelection() {
+		t.Fatalf("visible %v, selection %v; want nothing shown", list.visibleItems(), list.hasSelection())
+	}
+	if list.selectedIndex < 0 || list.selectedIndex >= list.Len() {
+		t.Errorf("cursor on %d of %d items", list.selectedIndex, list.Len())
+	}
+	list = press(t, list, "j", "k", " ", "e")
+	if list.currentMode != ModeNormal {
+		t.Errorf("mode %v after keys on an empty view, want normal", list.currentMode)
+	}
+}
+
+func TestLeavingSearchClearsInput(t *testing.T) {
+	for _, key := range []string{"enter", "esc"} {
+		list := typeText(t, press(t, newTestList(t, "milk", "bread"), "/"), "mi")
+		list = press(t, list, key)
+		if list.currentMode != ModeNormal || list.input.Content != "" || list.input.Cursor != 0 {
+			t.Errorf("after %s: mode %v, input %q at %d", key, list.currentMode, list.input.Content, list.input.Cursor)
+		}
+	}
+}
+
+func TestFilterByField(t *testing.T) {
+	list := newTestList(t, "call bob", "call amy", "field:phone=555 reminder")
+	if err := list.SetField(0, "phone", "555"); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.SetField(1, "phone", "123"); err != nil {
+		t.Fatal(err)
+	}
+	list = typeText(t, press(t, list, "/"), "field:Phone=555")
+	list = press(t, list, "enter")
+
+	var shown []string
+	for _, index := range list.visibleItems() {
+		shown = append(shown, list.items.at(index).Title)
+	}
+	if !slices.Equal(shown, []string{"call bob"}) {
+		t.Errorf("field:Phone=555 shows %q, want only %q", shown, "call bob")
+	}
+}
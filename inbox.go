@@ -0,0 +1,332 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// While the TUI runs it holds a lock file next to the list. lazylist --add
+// takes the same lock to write the list itself; when a live process already
+// holds it the new title is dropped into an inbox directory instead, so two
+// processes never write the file at once. The TUI drains the inbox every
+// second, and the next --add to get the lock drains it too. An inbox entry
+// is only removed once a save holding its title has succeeded, so a crash
+// in between adds it again rather than losing it.
+
+// inboxPollInterval is how often the TUI picks up titles added from the CLI.
+const inboxPollInterval = time.Second
+
+type inboxMsg struct {
+	entries []inboxEntry
+	err     error
+}
+
+// inboxEntry is one queued title and the file holding it.
+type inboxEntry struct {
+	file  string
+	title string
+}
+
+// inboxSavedMsg reports that files were saved into the list and removed
+// from the inbox.
+type inboxSavedMsg struct {
+	files []string
+	err   error
+}
+
+func lockPath(path string) string {
+	return path + ".lock"
+}
+
+func inboxDir(path string) string {
+	return path + ".inbox"
+}
+
+// errLocked is returned by acquireLock when a running process holds the
+// lock.
+var errLocked = errors.New("already open in lazylist")
+
+// acquireLock claims the list at path for this process and returns a
+// function that releases it. A lock left behind by a process that is no
+// longer running is taken over. The lock file is linked into place with the
+// pid already written, so no other process ever reads it empty and mistakes
+// it for a stale one.
+func acquireLock(path string) (func(), error) {
+	lock := lockPath(path)
+	tmp, err := os.CreateTemp(filepath.Dir(lock), filepath.Base(lock)+".*")
+	if err != nil {
+		return nil, fmt.Errorf("lock %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = fmt.Fprintf(tmp, "%d\n", os.Getpid())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock %s: %w", path, err)
+	}
+
+	for range 3 {
+		err := os.Link(tmp.Name(), lock)
+		if err == nil {
+			return func() { os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+		pid, alive, stale := readLock(lock)
+		if alive {
+			return nil, fmt.Errorf("%s is %w (pid %d)", path, errLocked, pid)
+		}
+		if stale == nil {
+			// Released since the link failed; try again.
+			continue
+		}
+		if err := removeStaleLock(lock, stale); err != nil {
+			return nil, fmt.Errorf("lock %s: %w", path, err)
+		}
+	}
+	return nil, fmt.Errorf("lock %s: could not take over a stale lock", path)
+}
+
+// removeStaleLock removes the lock file stale, read from lock, if it is
+// still in place. Several processes can find the same stale lock, and by
+// the time one of them removes it another may already have replaced it
+// with its own. So the file is first renamed aside, which only one process
+// can do to any one file, and what was moved is checked: if it is not the
+// stale file it is someone's new lock and is linked back.
+func removeStaleLock(lock string, stale os.FileInfo) error {
+	aside := fmt.Sprintf("%s.stale.%d.%d", lock, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(lock, aside); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer os.Remove(aside)
+	moved, err := os.Stat(aside)
+	if err != nil || os.SameFile(moved, stale) {
+		return err
+	}
+	if err := os.Link(aside, lock); err != nil && !errors.Is(err, os.ErrExist) {
+		return err
+	}
+	return nil
+}
+
+// lockOwner reports the pid holding the lock on path and whether that
+// process is still running.
+func lockOwner(path string) (int, bool) {
+	pid, alive, _ := readLock(lockPath(path))
+	return pid, alive
+}
+
+// readLock reads the lock file at lock. It returns the owner's pid and
+// whether it is running, and, when the owner is gone, the file's info so
+// it can be told apart from a lock written later. A missing lock has no
+// owner and no info.
+func readLock(lock string) (pid int, alive bool, stale os.FileInfo) {
+	f, err := os.Open(lock)
+	if err != nil {
+		return 0, false, nil
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, false, nil
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, false, info
+	}
+	pid, err = strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, false, info
+	}
+	if processRunning(pid) {
+		return pid, true, nil
+	}
+	return pid, false, info
+}
+
+// processRunning reports whether pid is a live process. Signal 0 checks
+// without sending anything; EPERM means the process exists but belongs to
+// another user.
+func processRunning(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = proc.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// addFromCLI adds title to the list at path for --add, along with any
+// titles still waiting in the inbox. It reports whether the title was
+// queued because another process holds the list rather than written
+// directly.
+func addFromCLI(path, title string) (bool, error) {
+	release, err := acquireLock(path)
+	if errors.Is(err, errLocked) {
+		return true, queueInbox(path, title)
+	}
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	items, err := LoadTodos(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	queued, err := readInbox(path, nil)
+	if err != nil {
+		return false, err
+	}
+	list := NewTodoList(items)
+	files := make([]string, len(queued))
+	for i, entry := range queued {
+		// A queued title that fails validation is skipped, as
+		// applyInbox skips it, rather than blocking this add.
+		list.AddItem(entry.title)
+		files[i] = entry.file
+	}
+	// The queued titles are saved even when title itself is rejected.
+	addErr := list.AddItem(title)
+	if err := SaveTodos(path, list.items.slice()); err != nil {
+		return false, err
+	}
+	if err := removeInboxFiles(files); err != nil {
+		return false, err
+	}
+	return false, addErr
+}
+
+// queueInbox writes title as its own file in the inbox. Entries are renamed
+// into place once complete, so a drain never sees half an entry.
+func queueInbox(path, title string) error {
+	dir := inboxDir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("queue %s: %w", path, err)
+	}
+	data, err := json.Marshal(title)
+	if err != nil {
+		return fmt.Errorf("queue %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "*.tmp")
+	if err != nil {
+		return fmt.Errorf("queue %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("queue %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("queue %s: %w", path, err)
+	}
+
+	name := fmt.Sprintf("%020d-%d.json", time.Now().UnixNano(), os.Getpid())
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("queue %s: %w", path, err)
+	}
+	return nil
+}
+
+// readInbox returns the entries queued for path, oldest first, leaving
+// them in place. Files in skip are already in the list and are left out.
+func readInbox(path string, skip []string) ([]inboxEntry, error) {
+	dir := inboxDir(path)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	slices.Sort(names)
+
+	var queued []inboxEntry
+	for _, name := range names {
+		file := filepath.Join(dir, name)
+		if slices.Contains(skip, file) {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return queued, err
+		}
+		var title string
+		if err := json.Unmarshal(data, &title); err != nil {
+			return queued, &ValidationError{Operation: "inbox", Err: fmt.Errorf("parse %s: %w", file, err)}
+		}
+		queued = append(queued, inboxEntry{file: file, title: title})
+	}
+	return queued, nil
+}
+
+// removeInboxFiles removes inbox entries whose titles have been saved.
+func removeInboxFiles(files []string) error {
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// pollInbox schedules the next inbox drain.
+func (t TodoList) pollInbox() tea.Cmd {
+	if t.store == nil {
+		return nil
+	}
+	path, skip := t.store.Path(), slices.Clone(t.inboxApplied)
+	return tea.Tick(inboxPollInterval, func(time.Time) tea.Msg {
+		entries, err := readInbox(path, skip)
+		return inboxMsg{entries: entries, err: err}
+	})
+}
+
+// applyInbox adds titles queued from the CLI through AddItem, like items
+// typed into the list. Their files are removed by the next save, which a
+// rejected title also asks for so its file goes too.
+func (t *TodoList) applyInbox(msg inboxMsg) {
+	var added []string
+	for _, entry := range msg.entries {
+		t.inboxApplied = append(t.inboxApplied, entry.file)
+		t.save()
+		if err := t.AddItem(entry.title); err != nil {
+			t.statusMsg = fmt.Sprintf("could not add %q from another shell: %v", entry.title, err)
+			continue
+		}
+		added = append(added, entry.title)
+	}
+
+	switch {
+	case msg.err != nil:
+		t.statusMsg = fmt.Sprintf("inbox: %v", msg.err)
+	case len(added) == 1:
+		t.statusMsg = fmt.Sprintf("added '%s' from another shell", added[0])
+	case len(added) > 1:
+		t.statusMsg = fmt.Sprintf("added %d items from another shell", len(added))
+	}
+}
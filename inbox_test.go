@@ -0,0 +1,197 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// TestAddFromCLIConcurrent runs many --add calls at once. Each title must
+// end up either in the list or in the inbox, and the last add to take the
+// lock must have drained the inbox of everything queued before it.
+func TestAddFromCLIConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todos.json")
+	const adds = 40
+
+	var wg sync.WaitGroup
+	errs := make(chan error, adds)
+	for i := range adds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := addFromCLI(path, fmt.Sprintf("task %d", i)); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("addFromCLI: %v", err)
+	}
+
+	// One more add with nothing running alongside it picks up whatever
+	// the others queued.
+	if queued, err := addFromCLI(path, "last"); err != nil || queued {
+		t.Fatalf("final add: queued %v, err %v; want written directly", queued, err)
+	}
+	items, err := LoadTodos(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := itemTitles(items)
+	if len(got) != adds+1 {
+		t.Fatalf("list holds %d items, want %d: %q", len(got), adds+1, got)
+	}
+	for i := range adds {
+		if title := fmt.Sprintf("task %d", i); !slices.Contains(got, title) {
+			t.Errorf("%q was lost", title)
+		}
+	}
+	if rest, _ := readInbox(path, nil); len(rest) > 0 {
+		t.Errorf("inbox still holds %v", rest)
+	}
+}
+
+func TestAddFromCLIQueuesWhileLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todos.json")
+	release, err := acquireLock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queued, err := addFromCLI(path, "from another shell")
+	release()
+	if err != nil || !queued {
+		t.Fatalf("queued %v, err %v; want queued", queued, err)
+	}
+	queuedEntries, err := readInbox(path, nil)
+	if err != nil || len(queuedEntries) != 1 || queuedEntries[0].title != "from another shell" {
+		t.Fatalf("inbox = %v, %v", queuedEntries, err)
+	}
+}
+
+// TestInboxIntoRunningList queues titles from many --add calls while a
+// list holds the lock, and drains them the way the TUI does. Entries must
+// stay in the inbox until the save that holds them, and every title must
+// reach the file exactly once.
+func TestInboxIntoRunningList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todos.json")
+	release, err := acquireLock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	list := *NewTodoList(nil)
+	list.store = NewStore(path)
+
+	poll := func() {
+		t.Helper()
+		entries, err := readInbox(path, list.inboxApplied)
+		if err != nil {
+			t.Fatal(err)
+		}
+		model, _ := list.update(inboxMsg{entries: entries})
+		list = model.(TodoList)
+		if !list.dirty {
+			return
+		}
+		// A quit here, before the save, must leave the entries queued.
+		for _, entry := range entries {
+			if _, err := os.Stat(entry.file); err != nil {
+				t.Fatalf("%q left the inbox before it was saved: %v", entry.title, err)
+			}
+		}
+		model, _ = list.update(list.saveCmd()())
+		list = model.(TodoList)
+	}
+
+	const adds = 20
+	var wg sync.WaitGroup
+	for i := range adds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if queued, err := addFromCLI(path, fmt.Sprintf("task %d", i)); err != nil || !queued {
+				t.Errorf("add: queued %v, err %v; want queued", queued, err)
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() { wg.Wait(); close(done) }()
+	for running := true; running; {
+		select {
+		case <-done:
+			running = false
+		default:
+		}
+		poll()
+	}
+
+	items, err := LoadTodos(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := itemTitles(items)
+	slices.Sort(got)
+	want := make([]string, adds)
+	for i := range want {
+		want[i] = fmt.Sprintf("task %d", i)
+	}
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("saved %q, want each of %q once", got, want)
+	}
+	if rest, _ := readInbox(path, nil); len(rest) > 0 || len(list.inboxApplied) > 0 {
+		t.Errorf("inbox still holds %v, pending %q", rest, list.inboxApplied)
+	}
+}
+
+// deadPid is a pid above any the kernel hands out, so no process has it.
+const deadPid = 1<<31 - 1
+
+func TestStaleLockTakenOverOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todos.json")
+	for round := range 100 {
+		if err := os.WriteFile(lockPath(path), []byte(fmt.Sprintf("%d\n", deadPid)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		const contenders = 8
+		var wg sync.WaitGroup
+		releases := make(chan func(), contenders)
+		for range contenders {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				release, err := acquireLock(path)
+				if err == nil {
+					releases <- release
+				} else if !errors.Is(err, errLocked) {
+					t.Errorf("acquireLock: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+		close(releases)
+		if len(releases) != 1 {
+			t.Fatalf("round %d: %d processes took over the stale lock, want 1", round, len(releases))
+		}
+		(<-releases)()
+	}
+}
+
+func TestLockOfOtherUsersProcessIsLive(t *testing.T) {
+	// pid 1 always runs; unless the test runs as root, signalling it
+	// fails with EPERM.
+	path := filepath.Join(t.TempDir(), "todos.json")
+	if err := os.WriteFile(lockPath(path), []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := acquireLock(path); !errors.Is(err, errLocked) {
+		t.Fatalf("acquireLock = %v, want %v", err, errLocked)
+	}
+}
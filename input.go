@@ -0,0 +1,72 @@
+package main
+
+import (
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// split returns the input content before and after the cursor.
+func (in InputContext) split() (string, string) {
+	runes := []rune(in.Content)
+	cursor := min(max(in.Cursor, 0), len(runes))
+	return string(runes[:cursor]), string(runes[cursor:])
+}
+
+// boundaries returns the rune offsets where each user-perceived character
+// (grapheme cluster) starts, followed by the total rune count. Moving the
+// cursor between these keeps combining marks and emoji sequences whole.
+func (in InputContext) boundaries() []int {
+	offsets := []int{0}
+	graphemes := uniseg.NewGraphemes(in.Content)
+	for graphemes.Next() {
+		offsets = append(offsets, offsets[len(offsets)-1]+len(graphemes.Runes()))
+	}
+	return offsets
+}
+
+// prevBoundary is the start of the character before the cursor.
+func (in InputContext) prevBoundary() int {
+	prev := 0
+	for _, offset := range in.boundaries() {
+		if offset >= in.Cursor {
+			break
+		}
+		prev = offset
+	}
+	return prev
+}
+
+// nextBoundary is the end of the character after the cursor.
+func (in InputContext) nextBoundary() int {
+	offsets := in.boundaries()
+	for _, offset := range offsets {
+		if offset > in.Cursor {
+			return offset
+		}
+	}
+	return offsets[len(offsets)-1]
+}
+
+// deleteToLineStart removes everything before the cursor.
+func (t *TodoList) deleteToLineStart() {
+	_, after := t.input.split()
+	t.input.Content = after
+	t.input.Cursor = 0
+}
+
+// deleteWordBackward removes the spaces just before the cursor and then the
+// word before them.
+func (t *TodoList) deleteWordBackward() {
+	before, after := t.input.split()
+	runes := []rune(before)
+	start := len(runes)
+	for start > 0 && unicode.IsSpace(runes[start-1]) {
+		start--
+	}
+	for start > 0 && !unicode.IsSpace(runes[start-1]) {
+		start--
+	}
+	t.input.Content = string(runes[:start]) + after
+	t.input.Cursor = start
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeleteToLineStart(t *testing.T) {
+	tests := []struct {
+		content string
+		cursor  int
+		want    string
+	}{
+		{"buy milk", 4, "milk"},
+		{"buy milk", 0, "buy milk"},
+		{"buy milk", 8, ""},
+		{"café au lait", 5, "au lait"},
+		{"牛乳を買う", 2, "を買う"},
+	}
+	for _, tt := range tests {
+		list := TodoList{input: InputContext{Content: tt.content, Cursor: tt.cursor}}
+		list.deleteToLineStart()
+		if list.input.Content != tt.want || list.input.Cursor != 0 {
+			t.Errorf("%q at %d: got %q at %d, want %q at 0", tt.content, tt.cursor, list.input.Content, list.input.Cursor, tt.want)
+		}
+	}
+}
+
+func TestDeleteWordBackward(t *testing.T) {
+	tests := []struct {
+		content    string
+		cursor     int
+		want       string
+		wantCursor int
+	}{
+		{"buy milk", 8, "buy ", 4},
+		{"buy milk  ", 10, "buy ", 4},
+		{"buy milk", 3, " milk", 0},
+		{"buy milk", 0, "buy milk", 0},
+		{"   ", 3, "", 0},
+		{"buy fresh milk", 9, "buy  milk", 4},
+		{"prendre un café", 15, "prendre un ", 11},
+		{"牛乳 を買う", 6, "牛乳 ", 3},
+	}
+	for _, tt := range tests {
+		list := TodoList{input: InputContext{Content: tt.content, Cursor: tt.cursor}}
+		list.deleteWordBackward()
+		if list.input.Content != tt.want || list.input.Cursor != tt.wantCursor {
+			t.Errorf("%q at %d: got %q at %d, want %q at %d",
+				tt.content, tt.cursor, list.input.Content, list.input.Cursor, tt.want, tt.wantCursor)
+		}
+	}
+}
+
+// TestInputEditingKeepsCharactersWhole drives typing, backspace and the
+// arrow keys over emoji with skin tones, CJK and combining marks, at the
+// start, middle and end of the text. Cursors count runes.
+func TestInputEditingKeepsCharactersWhole(t *testing.T) {
+	tests := []struct {
+		content    string
+		cursor     int
+		keys       []string
+		want       string
+		wantCursor int
+	}{
+		// a👍🏽b is four runes; the thumb and its skin tone are one character.
+		{"a👍🏽b", 4, []string{"left"}, "a👍🏽b", 3},
+		{"a👍🏽b", 3, []string{"left"}, "a👍🏽b", 1},
+		{"a👍🏽b", 0, []string{"left"}, "a👍🏽b", 0},
+		{"a👍🏽b", 1, []string{"right"}, "a👍🏽b", 3},
+		{"a👍🏽b", 4, []string{"right"}, "a👍🏽b", 4},
+		{"a👍🏽b", 0, []string{"backspace"}, "a👍🏽b", 0},
+		{"a👍🏽b", 3, []string{"backspace"}, "ab", 1},
+		{"a👍🏽b", 4, []string{"backspace", "backspace"}, "a", 1},
+		{"a👍🏽b", 0, []string{"insert x"}, "xa👍🏽b", 1},
+		{"a👍🏽b", 3, []string{"insert 日"}, "a👍🏽日b", 4},
+		{"a👍🏽b", 4, []string{"insert 🎉"}, "a👍🏽b🎉", 5},
+
+		{"日本語", 0, []string{"right", "right"}, "日本語", 2},
+		{"日本語", 3, []string{"left"}, "日本語", 2},
+		{"日本語", 0, []string{"backspace"}, "日本語", 0},
+		{"日本語", 2, []string{"backspace"}, "日語", 1},
+		{"日本語", 3, []string{"backspace"}, "日本", 2},
+		{"日本語", 0, []string{"insert x"}, "x日本語", 1},
+		{"日本語", 1, []string{"insert x"}, "日x本語", 2},
+		{"日本語", 3, []string{"insert x"}, "日本語x", 4},
+
+		// e followed by a combining acute accent is one character.
+		{"e\u0301x", 3, []string{"left", "left"}, "e\u0301x", 0},
+		{"e\u0301x", 0, []string{"right"}, "e\u0301x", 2},
+		{"e\u0301x", 2, []string{"right"}, "e\u0301x", 3},
+		{"e\u0301x", 2, []string{"backspace"}, "x", 0},
+		{"e\u0301x", 3, []string{"backspace"}, "e\u0301", 2},
+		{"e\u0301x", 0, []string{"insert y"}, "yéx", 1},
+		{"e\u0301x", 2, []string{"insert y"}, "e\u0301yx", 3},
+		{"e\u0301x", 3, []string{"insert y"}, "e\u0301xy", 4},
+	}
+	for _, tt := range tests {
+		list := TodoList{input: InputContext{Content: tt.content, Cursor: tt.cursor}}
+		for _, key := range tt.keys {
+			if text, ok := strings.CutPrefix(key, "insert "); ok {
+				list.insertAtCursor(text)
+				continue
+			}
+			list.editInput(keyMsg(key))
+		}
+		if list.input.Content != tt.want || list.input.Cursor != tt.wantCursor {
+			t.Errorf("%q at %d, %q: got %q at %d, want %q at %d",
+				tt.content, tt.cursor, tt.keys, list.input.Content, list.input.Cursor, tt.want, tt.wantCursor)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"iter"
+	"slices"
+	"sort"
+)
+
+// itemList holds the items in chunks that are never changed once built. A
+// change copies the chunks it touches and the small chunk table, so copying
+// the list itself — an undo step, or the snapshot a background save
+// writes — is free, and copies share every chunk the change left alone.
+// This keeps each key press proportional to what it changes rather than to
+// the length of the list.
+type itemList struct {
+	chunks [][]TodoItem
+	// starts is the index of the first item of each chunk.
+	starts []int
+	length int
+}
+
+// chunkSize is the most items a chunk holds. Inserting into a full chunk
+// splits it in two.
+const chunkSize = 256
+
+func newItemList(items []TodoItem) itemList {
+	var l itemList
+	for start := 0; start < len(items); start += chunkSize {
+		end := min(start+chunkSize, len(items))
+		l.chunks = append(l.chunks, slices.Clone(items[start:end]))
+		l.starts = append(l.starts, start)
+	}
+	l.length = len(items)
+	return l
+}
+
+func (l itemList) Len() int {
+	return l.length
+}
+
+// locate returns the chunk holding index and its offset in that chunk.
+// index must be in range.
+func (l itemList) locate(index int) (int, int) {
+	c := sort.Search(len(l.starts), func(c int) bool { return l.starts[c] > index }) - 1
+	return c, index - l.starts[c]
+}
+
+// at returns the item at index, which must be in range.
+func (l itemList) at(index int) TodoItem {
+	c, i := l.locate(index)
+	return l.chunks[c][i]
+}
+
+// all yields each index with a pointer to its item. The items belong to
+// chunks shared with other copies of the list and must not be modified.
+func (l itemList) all() iter.Seq2[int, *TodoItem] {
+	return func(yield func(int, *TodoItem) bool) {
+		index := 0
+		for _, chunk := range l.chunks {
+			for i := range chunk {
+				if !yield(index, &chunk[i]) {
+					return
+				}
+				index++
+			}
+		}
+	}
+}
+
+// slice returns a copy of the items as a plain slice.
+func (l itemList) slice() []TodoItem {
+	items := make([]TodoItem, 0, l.length)
+	for _, chunk := range l.chunks {
+		items = append(items, chunk...)
+	}
+	return items
+}
+
+// same reports whether l and other are the same version of the list: one
+// is a copy of the other with no change made since.
+func (l itemList) same(other itemList) bool {
+	if l.length != other.length || len(l.chunks) != len(other.chunks) {
+		return false
+	}
+	return len(l.chunks) == 0 || &l.chunks[0] == &other.chunks[0]
+}
+
+// sameChunk reports whether a and b are the same chunk.
+func sameChunk(a, b []TodoItem) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// own gives l its own chunk table so a change does not show through in
+// copies that share it.
+func (l *itemList) own() {
+	l.chunks = slices.Clone(l.chunks)
+}
+
+// reindex recomputes starts and length from the chunk sizes.
+func (l *itemList) reindex() {
+	l.starts = make([]int, len(l.chunks))
+	l.length = 0
+	for c, chunk := range l.chunks {
+		l.starts[c] = l.length
+		l.length += len(chunk)
+	}
+}
+
+// set replaces the item at index.
+func (l *itemList) set(index int, item TodoItem) {
+	l.update([]int{index}, func(old *TodoItem) { *old = item })
+}
+
+// update calls change on a copy of each item in indexes, which must be in
+// range and ascending, copying every chunk it touches once.
+func (l *itemList) update(indexes []int, change func(*TodoItem)) {
+	l.own()
+	copied := -1
+	for _, index := range indexes {
+		c, i := l.locate(index)
+		if c != copied {
+			l.chunks[c] = slices.Clone(l.chunks[c])
+			copied = c
+		}
+		change(&l.chunks[c][i])
+	}
+}
+
+// insert puts item at index, moving the items from index on down one.
+// index may be Len to append.
+func (l *itemList) insert(index int, item TodoItem) {
+	l.own()
+	if len(l.chunks) == 0 {
+		l.chunks = [][]TodoItem{{item}}
+		l.reindex()
+		return
+	}
+	c, i := len(l.chunks)-1, len(l.chunks[len(l.chunks)-1])
+	if index < l.length {
+		c, i = l.locate(index)
+	}
+	chunk := slices.Insert(slices.Clip(l.chunks[c]), i, item)
+	if len(chunk) > chunkSize {
+		half := len(chunk) / 2
+		l.chunks = slices.Insert(l.chunks, c+1, slices.Clone(chunk[half:]))
+		chunk = chunk[:half:half]
+	}
+	l.chunks[c] = chunk
+	l.reindex()
+}
+
+// append adds item after the last item.
+func (l *itemList) append(item TodoItem) {
+	l.insert(l.length, item)
+}
+
+// remove deletes the items in indexes, which must be in range and
+// ascending, copying only the chunks that lose an item.
+func (l *itemList) remove(indexes []int) {
+	if len(indexes) == 0 {
+		return
+	}
+	chunks := make([][]TodoItem, 0, len(l.chunks))
+	next := 0
+	for c, chunk := range l.chunks {
+		end := l.starts[c] + len(chunk)
+		if next == len(indexes) || indexes[next] >= end {
+			chunks = append(chunks, chunk)
+			continue
+		}
+		kept := make([]TodoItem, 0, len(chunk))
+		for i := range chunk {
+			if next < len(indexes) && indexes[next] == l.starts[c]+i {
+				next++
+				continue
+			}
+			kept = append(kept, chunk[i])
+		}
+		if len(kept) > 0 {
+			chunks = append(chunks, kept)
+		}
+	}
+	l.chunks = chunks
+	l.reindex()
+}
+
+// allIndexes returns 0 through n-1.
+func allIndexes(n int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func itemTitles(items []TodoItem) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = item.Title
+	}
+	return out
+}
+
+// TestItemListMatchesSlice applies random changes to an itemList and to a
+// plain slice, checking after each that they agree and that a copy taken
+// before the change still holds the old items.
+func TestItemListMatchesSlice(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	var want []TodoItem
+	list := newItemList(nil)
+	next := 0
+	newTitle := func() TodoItem {
+		next++
+		return TodoItem{Title: fmt.Sprintf("item %d", next)}
+	}
+
+	for step := range 3000 {
+		before, beforeWant := list, slices.Clone(want)
+		switch op := rng.IntN(10); {
+		case op < 4 || len(want) == 0:
+			index := rng.IntN(len(want) + 1)
+			item := newTitle()
+			list.insert(index, item)
+			want = slices.Insert(want, index, item)
+		case op < 6:
+			var indexes []int
+			for i := range want {
+				if rng.IntN(50) == 0 {
+					indexes = append(indexes, i)
+				}
+			}
+			list.remove(indexes)
+			for _, index := range slices.Backward(indexes) {
+				want = slices.Delete(want, index, index+1)
+			}
+		default:
+			index := rng.IntN(len(want))
+			item := newTitle()
+			list.set(index, item)
+			want[index] = item
+		}
+
+		if got := itemTitles(list.slice()); !slices.Equal(got, itemTitles(want)) {
+			t.Fatalf("step %d: items = %q, want %q", step, got, itemTitles(want))
+		}
+		if got := itemTitles(before.slice()); !slices.Equal(got, itemTitles(beforeWant)) {
+			t.Fatalf("step %d: the change showed through in an earlier copy", step)
+		}
+		for i := range want {
+			if list.at(i).Title != want[i].Title {
+				t.Fatalf("step %d: at(%d) = %q, want %q", step, i, list.at(i).Title, want[i].Title)
+			}
+		}
+	}
+}
+
+// TestVisibleItemsCacheFollowsChanges checks the cached visible rows
+// against a fresh scan after each change to a filtered list.
+func TestVisibleItemsCacheFollowsChanges(t *testing.T) {
+	items := make([]TodoItem, 2000)
+	for i := range items {
+		items[i] = TodoItem{Title: fmt.Sprintf("item %d", i), Completed: i%3 == 0}
+	}
+	list := *NewTodoList(items)
+	list.filterMode = FilterActive
+
+	rng := rand.New(rand.NewPCG(3, 4))
+	for step := range 500 {
+		index := rng.IntN(list.Len())
+		switch rng.IntN(3) {
+		case 0:
+			list.ToggleItem(index)
+		case 1:
+			list.DeleteItem(index)
+		default:
+			list.items.insert(index, TodoItem{Title: "new"})
+		}
+		if got, want := list.visibleItems(), list.scanVisible("", nil); !slices.Equal(got, want) {
+			t.Fatalf("step %d: visibleItems = %v, want %v", step, got, want)
+		}
+	}
+}
+
+func TestItemsReturnsCopy(t *testing.T) {
+	list := newTestList(t, "a", "b")
+	list.items.set(0, TodoItem{Title: "a", Tags: []string{"home"}, Fields: map[string]string{"owner": "sam"}})
+
+	items := list.Items()
+	items[0].Title = "changed"
+	items[0].Tags[0] = "changed"
+	items[0].Fields["owner"] = "changed"
+	_ = append(items[:1], TodoItem{Title: "appended"})
+
+	item, ok := list.At(0)
+	if !ok || item.Title != "a" || item.Tags[0] != "home" || item.Fields["owner"] != "sam" {
+		t.Errorf("changing the copy reached the list: %+v", item)
+	}
+	assertTitles(t, list, "a", "b")
+
+	item.Tags[0] = "changed"
+	if again, _ := list.At(0); again.Tags[0] != "home" {
+		t.Errorf("changing At's item reached the list: %+v", again)
+	}
+	if _, ok := list.At(list.Len()); ok {
+		t.Error("At past the end reported an item")
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// latencyItems is the list size the key path is held to updateBudget at.
+const latencyItems = 50_000
+
+// newLongList returns a list of latencyItems items, every tenth completed,
+// saving to a temporary file so mutations take the save path.
+func newLongList(t *testing.T) TodoList {
+	t.Helper()
+	items := make([]TodoItem, latencyItems)
+	for i := range items {
+		items[i] = TodoItem{Title: fmt.Sprintf("item %d #tag%d", i, i%7), Completed: i%10 == 0}
+	}
+	list := NewTodoList(items)
+	list.store = NewStore(filepath.Join(t.TempDir(), "todos.json"))
+	next, _ := send(t, *list, tea.WindowSizeMsg{Width: 120, Height: 40})
+	return press(t, next, "j")
+}
+
+// TestKeyLatencyLongList checks that the keys used while working through a
+// long list stay within updateBudget. Sorting (s, P), toggling everything
+// (a) and clearing completed items (C) rewrite the whole list by nature and
+// are left out.
+func TestKeyLatencyLongList(t *testing.T) {
+	if testing.Short() || raceEnabled {
+		t.Skip("timing test")
+	}
+	steps := []struct {
+		name string
+		msgs []tea.Msg
+	}{
+		{"down", []tea.Msg{keyMsg("j")}},
+		{"up", []tea.Msg{keyMsg("k")}},
+		{"page down", []tea.Msg{keyMsg("pgdown")}},
+		{"bottom", []tea.Msg{keyMsg("G")}},
+		{"top", []tea.Msg{keyMsg("g")}},
+		{"toggle", []tea.Msg{keyMsg(" ")}},
+		{"priority", []tea.Msg{keyMsg("p")}},
+		{"waiting", []tea.Msg{keyMsg("w")}},
+		{"move", []tea.Msg{keyMsg("J")}},
+		{"delete", []tea.Msg{keyMsg("d"), keyMsg("y")}},
+		{"undo", []tea.Msg{keyMsg("u")}},
+		{"redo", []tea.Msg{keyMsg("ctrl+r")}},
+		{"add", []tea.Msg{keyMsg("n"), keyMsg("x"), keyMsg("y"), keyMsg("enter")}},
+		{"edit", []tea.Msg{keyMsg("e"), keyMsg("!"), keyMsg("enter")}},
+		{"filter mode", []tea.Msg{keyMsg("f")}},
+		{"inbox poll", []tea.Msg{inboxMsg{}}},
+		{"window resize", []tea.Msg{tea.WindowSizeMsg{Width: 100, Height: 30}}},
+	}
+
+	list := newLongList(t)
+	for _, step := range steps {
+		for _, msg := range step.msgs {
+			// The best of a few runs, so a stray GC pause does not fail the
+			// test; each run starts from the same list.
+			best := time.Duration(1 << 62)
+			var next TodoList
+			for range 5 {
+				start := time.Now()
+				next, _ = send(t, list, msg)
+				best = min(best, time.Since(start))
+			}
+			if best > updateBudget {
+				t.Errorf("%s: %v took %v, budget %v", step.name, msg, best, updateBudget)
+			}
+			list = next
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/mattn/go-runewidth"
+)
+
+const ellipsis = "…"
+
+// A component renders one region of the screen. See view.go.
+type component func(t TodoList, width int) []string
+
+// Components stacked above and below the list, top to bottom. The list gets
+// whatever height they leave over.
+var (
+	aboveList = []component{TodoList.headerLines}
+	belowList = []component{TodoList.panelLines, TodoList.statusLines, TodoList.footerLines}
+)
+
+// layout assembles the components into a frame.
+func (t TodoList) layout() string {
+	above, list, below := t.frame()
+
+	lines := make([]string, 0, len(above)+len(list)+len(below))
+	lines = append(lines, above...)
+	lines = append(lines, list...)
+	lines = append(lines, below...)
+
+	var sb strings.Builder
+	sb.Grow(frameSize(lines))
+	for i, line := range lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(line)
+	}
+	return sb.String()
+}
+
+// frame renders the lines above, in and below the list as they appear on
+// screen. Fixed regions are rendered first so the list can be given the
+// remaining rows; an unknown terminal height leaves the list unbounded.
+func (t TodoList) frame() (above, list, below []string) {
+	above = renderComponents(t, aboveList)
+	below = renderComponents(t, belowList)
+
+	listHeight := 0
+	if t.height > 0 {
+		listHeight = max(t.height-len(above)-len(below), 2)
+	}
+	list = t.listLines(t.width, listHeight)
+
+	// A terminal too short for the whole frame loses the header first and
+	// then lines from the bottom, so the list rows and what is being typed
+	// stay on screen the longest.
+	if excess := len(above) + len(list) + len(below) - t.height; t.height > 0 && excess > 0 {
+		drop := min(excess, len(above))
+		above, excess = above[drop:], excess-drop
+		drop = min(excess, len(below))
+		below, excess = below[:len(below)-drop], excess-drop
+		list = list[:len(list)-excess]
+	}
+	return above, list, below
+}
+
+func renderComponents(t TodoList, components []component) []string {
+	var lines []string
+	for _, render := range components {
+		lines = append(lines, render(t, t.width)...)
+	}
+	return lines
+}
+
+// breakpoint is which optional parts of the screen fit at a terminal width.
+// Every width-dependent choice about what to show is made in breakpointFor.
+type breakpoint struct {
+	// dueDates shows due dates on item rows; without them the selected
+	// item's date moves to the status area.
+	dueDates bool
+	// extras shows custom fields and warning markers on item rows.
+	extras bool
+	// markers shows priority markers before titles.
+	markers bool
+	// compact limits the status area and footer to a single line each, the
+	// footer holding one segment.
+	compact bool
+}
+
+// breakpointFor picks what fits at width: everything from 80 columns, no
+// due dates below that, no fields or warning markers below 60, and only
+// cursor, checkbox and title with single-segment chrome below 40. An
+// unknown width shows everything.
+func breakpointFor(width int) breakpoint {
+	switch {
+	case width <= 0 || width >= 80:
+		return breakpoint{dueDates: true, extras: true, markers: true}
+	case width >= 60:
+		return breakpoint{extras: true, markers: true}
+	case width >= 40:
+		return breakpoint{markers: true}
+	}
+	return breakpoint{compact: true}
+}
+
+// frameSize is the length of lines joined by newlines, so the frame buffer
+// is allocated once at the size of what is on screen.
+func frameSize(lines []string) int {
+	size := len(lines)
+	for _, line := range lines {
+		size += len(line)
+	}
+	return size
+}
+
+// footerHint is one key hint in the footer. When the footer does not fit the
+// terminal, hints with the highest drop value are removed first.
+type footerHint struct {
+	text string
+	drop int
+}
+
+var normalModeHints = []footerHint{
+	{"up/down: move cursor", 1},
+	{"g/G: top/bottom", 6},
+	{"pgup/pgdn: page", 8},
+	{"enter/space: toggle", 2},
+	{"a: toggle all", 6},
+	{"n: new item", 3},
+	{"e: edit", 4},
+	{"d: delete", 5},
+	{"C: clear completed", 7},
+	{"p: cycle priority", 7},
+	{"s/P: sort by due/priority", 7},
+	{"K/J: move item", 7},
+	{"u/ctrl+r: undo/redo", 7},
+	{"ctrl+p: go to", 8},
+	{"/: filter", 8},
+	{"f: cycle filter", 8},
+	{"t: tag filter", 8},
+	{"i: details", 9},
+	{"x: export", 9},
+	{"w: waiting", 7},
+	{"o: open link", 8},
+	{"m: simple mode", 9},
+	{"q/esc: quit", 0},
+}
+
+// fitHints joins hints into one line no wider than width, dropping the least
+// important hints first and truncating if even the last hint does not fit. A
+// width of zero means the terminal size is not known yet.
+func fitHints(hints []footerHint, width int) string {
+	kept := slices.Clone(hints)
+	if width <= 0 {
+		return joinHints(kept)
+	}
+	// Each hint is measured once; the line is as wide as its hints plus
+	// the separators between them.
+	widths := make([]int, len(kept))
+	total := len(hintSeparator) * (len(kept) - 1)
+	for i, hint := range kept {
+		widths[i] = textWidth(hint.text)
+		total += widths[i]
+	}
+	for total > width && len(kept) > 1 {
+		worst := 0
+		for i, hint := range kept {
+			if hint.drop > kept[worst].drop {
+				worst = i
+			}
+		}
+		total -= widths[worst] + len(hintSeparator)
+		kept = slices.Delete(kept, worst, worst+1)
+		widths = slices.Delete(widths, worst, worst+1)
+	}
+	return truncateToWidth(joinHints(kept), width)
+}
+
+// hintSeparator goes between footer hints.
+const hintSeparator = ", "
+
+func joinHints(hints []footerHint) string {
+	texts := make([]string, len(hints))
+	for i, hint := range hints {
+		texts[i] = hint.text
+	}
+	return strings.Join(texts, hintSeparator)
+}
+
+// displayWidth measures strings in terminal cells, so wide CJK characters and
+// emoji count as two. Set EastAsianWidth for terminals that also render
+// ambiguous-width characters wide.
+var displayWidth = runewidth.NewCondition()
+
+// textWidth is displayWidth.StringWidth with a shortcut for printable
+// ASCII, where every byte is one cell.
+func textWidth(s string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] < ' ' || s[i] > '~' {
+			return displayWidth.StringWidth(s)
+		}
+	}
+	return len(s)
+}
+
+// cellWidth is the width of styled text: displayWidth of s without its
+// escape sequences. Measuring with the same rules truncateToWidth cuts by
+// keeps the two from disagreeing about wide and ambiguous characters.
+func cellWidth(s string) int {
+	return textWidth(ansi.Strip(s))
+}
+
+// truncateToWidth cuts s to at most width cells, ending it with an ellipsis
+// when anything was removed. A width of zero leaves s untouched.
+func truncateToWidth(s string, width int) string {
+	if width <= 0 || (len(s) <= width && textWidth(s) == len(s)) {
+		return s
+	}
+	return displayWidth.Truncate(s, width, ellipsis)
+}
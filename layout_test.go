@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// newFrameList returns a list of n items in a 120x40 terminal.
+func newFrameList(t testing.TB, n int) TodoList {
+	items := make([]TodoItem, n)
+	for i := range items {
+		items[i] = TodoItem{Title: fmt.Sprintf("item %d", i), Completed: i%4 == 0}
+	}
+	model, _ := NewTodoList(items).Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return model.(TodoList)
+}
+
+// TestViewAllocationsIndependentOfLength checks that a frame allocates for
+// the rows on screen, not for every item in the list.
+func TestViewAllocationsIndependentOfLength(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	const budget = 10 << 10
+	list := newFrameList(t, 5000)
+	list.View()
+
+	const frames = 50
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for range frames {
+		list.View()
+	}
+	runtime.ReadMemStats(&after)
+	if perFrame := (after.TotalAlloc - before.TotalAlloc) / frames; perFrame > budget {
+		t.Errorf("a frame of a 5000-item list allocates %d bytes, budget %d", perFrame, budget)
+	}
+}
+
+func BenchmarkViewLongList(b *testing.B) {
+	list := newFrameList(b, 5000)
+	b.ReportAllocs()
+	for b.Loop() {
+		list.View()
+	}
+}
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// TestBreakpointGolden renders the same list at one width inside each
+// breakpoint and compares the unstyled frame with testdata/breakpoints.
+// Run with -update after an intended layout change.
+func TestBreakpointGolden(t *testing.T) {
+	due := Date{Year: 2099, Month: 5, Day: 17}
+	items := []TodoItem{
+		{Title: "pay rent", DueDate: &due, Priority: PriorityHigh, Tags: []string{"home"}},
+		{Title: "renew passport before the summer trip abroad", Tags: []string{"travel", "admin"}, Fields: map[string]string{"owner": "sam"}},
+		{Title: "water plants", Completed: true},
+		{Title: "water plants", Priority: PriorityLow},
+	}
+	for _, width := range []int{30, 50, 70, 100} {
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			list, _ := send(t, *NewTodoList(items), tea.WindowSizeMsg{Width: width, Height: 14})
+			got := ansi.Strip(list.View()) + "\n"
+
+			path := filepath.Join("testdata", "breakpoints", fmt.Sprintf("width-%d.golden", width))
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("frame at %d columns:\n%s\nwant:\n%s", width, got, want)
+			}
+		})
+	}
+}
+
+// TestFrameFitsShortTerminals checks that a terminal too short for the
+// header, list and footer still gets a frame no taller than it.
+func TestFrameFitsShortTerminals(t *testing.T) {
+	for height := 1; height <= 8; height++ {
+		model, _ := newFrameList(t, 20).Update(tea.WindowSizeMsg{Width: 60, Height: height})
+		normal := model.(TodoList)
+		model, _ = normal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+		input := model.(TodoList)
+
+		for _, list := range []TodoList{normal, input} {
+			if lines := strings.Count(list.View(), "\n") + 1; lines > height {
+				t.Errorf("%d-line terminal in %s mode: frame is %d lines", height, modes[list.currentMode].name, lines)
+			}
+		}
+	}
+}
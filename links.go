@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// urlPattern finds http(s) links in titles and field values.
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// urls returns the links in the item's title and fields, in order of
+// appearance and without duplicates.
+func (item TodoItem) urls() []string {
+	texts := []string{item.Title}
+	for _, key := range slices.Sorted(maps.Keys(item.Fields)) {
+		texts = append(texts, item.Fields[key])
+	}
+
+	var urls []string
+	for _, text := range texts {
+		for _, url := range urlPattern.FindAllString(text, -1) {
+			// Punctuation ending a sentence is not part of the link.
+			url = strings.TrimRight(url, ".,;:!?)]'")
+			if !slices.Contains(urls, url) {
+				urls = append(urls, url)
+			}
+		}
+	}
+	return urls
+}
+
+// openCommand is the program that opens url in the user's browser.
+func openCommand(url string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url)
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	}
+	return exec.Command("xdg-open", url)
+}
+
+// openURL launches the browser in the background; a failure comes back as
+// an error for the status line.
+func openURL(url string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := openCommand(url)
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("open %s: %w", url, err)
+		}
+		go cmd.Wait()
+		return nil
+	}
+}
+
+// openLinks opens the selected item's only link, or a picker when it has
+// several.
+func (t *TodoList) openLinks() (tea.Cmd, bool) {
+	if !t.hasSelection() {
+		return nil, false
+	}
+	urls := t.items.at(t.selectedIndex).urls()
+	switch len(urls) {
+	case 0:
+		return nil, false
+	case 1:
+		t.statusMsg = "opening " + urls[0]
+		return openURL(urls[0]), true
+	}
+	t.links = urls
+	t.linkCursor = 0
+	t.currentMode = ModeLinks
+	return nil, true
+}
+
+func (t TodoList) handleLinksMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+		choice := int(key[0] - '1')
+		if choice >= len(t.links) {
+			return t, t.signalInvalid()
+		}
+		t.linkCursor = choice
+		key = "enter"
+	}
+
+	switch key {
+	case "ctrl+c":
+		return t, tea.Quit
+
+	case "esc", "q":
+		t.exitLinksMode()
+
+	case "up", "k":
+		t.linkCursor = (t.linkCursor + len(t.links) - 1) % len(t.links)
+
+	case "down", "j":
+		t.linkCursor = (t.linkCursor + 1) % len(t.links)
+
+	case "enter":
+		url := t.links[t.linkCursor]
+		t.exitLinksMode()
+		t.statusMsg = "opening " + url
+		return t, openURL(url)
+	}
+	return t, nil
+}
+
+func (t *TodoList) exitLinksMode() {
+	t.currentMode = ModeNormal
+	t.links = nil
+	t.linkCursor = 0
+}
+
+func (t TodoList) linkLines(width int) []string {
+	lines := []string{truncateToWidth("open which link? (1-9 or enter, esc to cancel)", width)}
+	for i, url := range t.links {
+		cursor := "  "
+		if i == t.linkCursor {
+			cursor = "> "
+		}
+		lines = append(lines, truncateToWidth(fmt.Sprintf("%s%d. %s", cursor, i+1, url), width))
+	}
+	return lines
+}
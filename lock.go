@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maskedTitle replaces every title while the list is locked.
+const maskedTitle = "•••"
+
+type idleMsg struct {
+	seq int
+}
+
+// resetIdleTimer restarts the idle countdown after user input. Only one timer
+// is ever meaningful: older ones carry a stale seq and are ignored, and no
+// timer runs at all when the idle lock is disabled.
+func (t *TodoList) resetIdleTimer() tea.Cmd {
+	if t.idleLockAfter <= 0 {
+		return nil
+	}
+	t.idleSeq++
+	return idleTimer(t.idleLockAfter, t.idleSeq)
+}
+
+func idleTimer(d time.Duration, seq int) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return idleMsg{seq: seq}
+	})
+}
+
+func (t *TodoList) lock() {
+	if t.currentMode == ModeLocked {
+		return
+	}
+	t.lockedFrom = t.currentMode
+	t.lockedInput = t.input
+	t.currentMode = ModeLocked
+	t.input = InputContext{}
+}
+
+func (t *TodoList) unlock() {
+	t.currentMode = t.lockedFrom
+	t.input = t.lockedInput
+	t.lockedInput = InputContext{}
+}
+
+// handleLockedMode reads the passphrase. Every other key is swallowed so
+// nothing destructive can happen while the screen is unattended.
+func (t TodoList) handleLockedMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return t, tea.Quit
+
+	case tea.KeyEnter:
+		if t.input.Content != t.lockPhrase {
+			t.input = InputContext{}
+			return t, t.signalInvalid()
+		}
+		t.unlock()
+
+	case tea.KeyEscape:
+		t.input = InputContext{}
+
+	default:
+		t.editInput(msg)
+	}
+	return t, nil
+}
+
+func (t TodoList) lockLines(width int) []string {
+	session := time.Since(t.sessionStart).Round(time.Minute)
+	lines := []string{truncateToWidth(fmt.Sprintf("locked after %s idle, session %s", t.idleLockAfter, session), width)}
+	if t.lockPhrase == "" {
+		return append(lines, truncateToWidth("press enter to unlock", width))
+	}
+	return append(lines, truncateToWidth("passphrase: "+strings.Repeat("*", len([]rune(t.input.Content))), width))
+}
@@ -2,12 +2,24 @@ package main
 
 import (
 	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	invertedStyle = lipgloss.NewStyle().Reverse(true)
+	dimStyle      = lipgloss.NewStyle().Faint(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
 )
 
 type AppMode int
@@ -16,6 +28,12 @@ type InputAction int
 const (
 	ModeInput AppMode = iota + 1
 	ModeNormal
+	ModePalette
+	ModeLocked
+	ModeSearch
+	ModeConfirm
+	ModeLinks
+	ModePaste
 )
 
 const (
@@ -23,47 +41,212 @@ const (
 	ActionCreate
 )
 
+type modeInfo struct {
+	name  string
+	color lipgloss.Color
+}
+
+// modes names every AppMode for the mode line. New modes must be registered
+// here to be shown.
+var modes = map[AppMode]modeInfo{
+	ModeNormal:  {name: "NORMAL", color: lipgloss.Color("12")},
+	ModeInput:   {name: "INSERT", color: lipgloss.Color("10")},
+	ModePalette: {name: "GO TO", color: lipgloss.Color("13")},
+	ModeLocked:  {name: "LOCKED", color: lipgloss.Color("9")},
+	ModeSearch:  {name: "FILTER", color: lipgloss.Color("11")},
+	ModeConfirm: {name: "CONFIRM", color: lipgloss.Color("208")},
+	ModeLinks:   {name: "LINKS", color: lipgloss.Color("14")},
+	ModePaste:   {name: "PASTE", color: lipgloss.Color("10")},
+}
+
 type TodoItem struct {
-	Title     string
-	Completed bool
+	Title     string            `json:"title"`
+	Completed bool              `json:"completed"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	DueDate   *Date             `json:"due,omitempty"`
+	Priority  Priority          `json:"priority,omitempty"`
+	Waiting   bool              `json:"waiting,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	// CreatedAt and CompletedAt are zero for items saved before they were
+	// recorded.
+	CreatedAt   time.Time `json:"createdAt,omitzero"`
+	CompletedAt time.Time `json:"completedAt,omitzero"`
 }
 
 type InputContext struct {
+	// Cursor is a rune offset into Content, so it never splits a UTF-8
+	// sequence.
 	Cursor     int
 	Content    string
 	InitialVal string
 	Action     InputAction
+	// Index is the item being edited when Action is ActionEdit, and
+	// editing is that item as it was when the edit began.
+	Index   int
+	editing TodoItem
 }
 
+type Severity int
+
+const (
+	// SeverityError is the zero value so existing errors stay blocking.
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
 type ValidationError struct {
 	Operation string
 	Err       error
+	Severity  Severity
 }
 
 func (e *ValidationError) Error() string {
+	if e.Severity == SeverityWarning {
+		return fmt.Sprintf("todo warning: %s: %v", e.Operation, e.Err)
+	}
 	return fmt.Sprintf("todo operation: %s: %v", e.Operation, e.Err)
 }
 
+// warningMarker flags items and input that have validation warnings.
+const warningMarker = "⚠"
+
+// maxTitleLength is the rune count above which a title draws a warning.
+const maxTitleLength = 100
+
+// deleteRestoreWindow is how long the notice offering to restore a deleted
+// item stays up. u restores it through the undo history, which outlasts the
+// notice.
+const deleteRestoreWindow = 30 * time.Second
+
+type DeletedItem struct {
+	Item TodoItem
+	seq  int
+}
+
+type deleteExpiredMsg struct {
+	seq int
+}
+
+// visualBellDuration is how long the header stays inverted after an invalid
+// action.
+const visualBellDuration = 150 * time.Millisecond
+
+type visualBellExpiredMsg struct {
+	seq int
+}
+
 type TodoList struct {
-	selectedIndex int
-	currentMode   AppMode
-	lastErr       error
-	items         []TodoItem
-	input         InputContext
+	selectedIndex   int
+	currentMode     AppMode
+	lastErr         error
+	lastErrSeq      int
+	items           itemList
+	input           InputContext
+	recentlyDeleted *DeletedItem
+	deleteSeq       int
+	paletteCursor   int
+	audibleBell     bool
+	visualBell      bool
+	visualBellOn    bool
+	visualBellSeq   int
+	titleRules      TitleRules
+	statusMsg       string
+	width           int
+	height          int
+	idleLockAfter   time.Duration
+	idleSeq         int
+	lockPhrase      string
+	lockedFrom      AppMode
+	lockedInput     InputContext
+	drafts          []draft
+	draftOffered    bool
+	draftOfferSeq   int
+	simpleMode      bool
+	actionMenu      bool
+	mouse           bool
+	showModeLine    bool
+	store           *Store
+	dirty           bool
+	saveSeq         int
+	undoStack       []snapshot
+	redoStack       []snapshot
+	pendingNumber   int
+	sessionStart    time.Time
+	// filter is the locked title filter; empty shows every item.
+	filter     string
+	filterMode FilterMode
+	// tagFilter shows only items with this tag; empty shows every item.
+	tagFilter string
+	// count is the pending count prefix in normal mode.
+	count   int
+	confirm confirmation
+	// confirmDelete asks before d deletes an item or C clears the
+	// completed ones.
+	confirmDelete bool
+	// links are the URLs offered by the link picker.
+	links      []string
+	linkCursor int
+	// viewportHeight is the number of lines the list had at the last
+	// update, and scrollOffset the first visible row it showed.
+	viewportHeight int
+	scrollOffset   int
+	// showDetails shows the selected item's age under its row.
+	showDetails bool
+	// inboxApplied are the inbox files whose titles are in the list but
+	// not yet known to be on disk. The poll skips them, and the save that
+	// writes them removes them.
+	inboxApplied []string
+	// unboundAt is when an unbound key last signalled, for
+	// unboundKeyCooldown.
+	unboundAt time.Time
+	// paste is a multi-line paste waiting for the user to choose how to
+	// add it.
+	paste string
+	// visible caches visibleItems between updates. It is shared by every
+	// copy of the list, which is why it sits behind a pointer.
+	visible *visibleCache
+	// completed indexes the completed titles for the duplicate warning.
+	completed *completedIndex
 }
 
-func NewTodoList(initialItems []string) *TodoList {
-	listItems := make([]TodoItem, len(initialItems))
+func NewTodoList(items []TodoItem) *TodoList {
+	return &TodoList{
+		items:         newItemList(items),
+		currentMode:   ModeNormal,
+		visualBell:    true,
+		confirmDelete: true,
+		sessionStart:  time.Now(),
+		visible:       &visibleCache{},
+		completed:     &completedIndex{},
+	}
+}
 
-	for i, item := range initialItems {
-		listItems[i] = TodoItem{
-			Title: item,
-		}
+// save marks the list as changed. Update turns that into a background write,
+// so mutations never touch the disk on the key path.
+func (t *TodoList) save() {
+	if t.store != nil {
+		t.dirty = true
 	}
+}
 
-	return &TodoList{
-		items:       listItems,
-		currentMode: ModeNormal,
+// saveCmd writes a snapshot of the items in the background. A failed write
+// comes back as an error message and is shown in the status line, so it is
+// never silently dropped.
+func (t *TodoList) saveCmd() tea.Cmd {
+	t.dirty = false
+	t.saveSeq++
+	// The list shares its chunks with later versions instead of being
+	// copied; the copy into a slice happens in the background.
+	store, seq, items := t.store, t.saveSeq, t.items
+	inboxFiles := slices.Clone(t.inboxApplied)
+	return func() tea.Msg {
+		if err := store.SaveIfNewer(seq, items.slice()); err != nil {
+			return err
+		}
+		if len(inboxFiles) > 0 {
+			return inboxSavedMsg{files: inboxFiles, err: removeInboxFiles(inboxFiles)}
+		}
+		return nil
 	}
 }
 
@@ -76,52 +259,398 @@ func validateItemTitle(title string) error {
 	return nil
 }
 
+// titleWarnings returns the non-blocking problems with title. skip is the
+// index of the item being edited, or -1, so an item is never its own
+// duplicate.
+func (t *TodoList) titleWarnings(title string, skip int) []*ValidationError {
+	var warnings []*ValidationError
+	warn := func(msg string) {
+		warnings = append(warnings, &ValidationError{Operation: "validate", Err: errors.New(msg), Severity: SeverityWarning})
+	}
+
+	if utf8.RuneCountInString(title) > maxTitleLength {
+		warn("title is very long")
+	}
+	if t.completedDuplicate(title, skip) {
+		warn("looks like a duplicate of a completed item")
+	}
+	for _, field := range strings.Fields(title) {
+		if strings.HasPrefix(field, "due:") {
+			warn("contains an unresolved due: token")
+			break
+		}
+	}
+	return warnings
+}
+
+// itemWarnings are the warnings for the item at index. They are worked out
+// when the item is drawn rather than stored, so they hold for items loaded
+// from disk and follow changes to the rest of the list.
+func (t *TodoList) itemWarnings(index int) []*ValidationError {
+	return t.titleWarnings(t.items.at(index).Title, index)
+}
+
 func (t *TodoList) isValidIndex(index int) bool {
-	return index >= 0 && index < len(t.items)
+	return index >= 0 && index < t.items.Len()
+}
+
+// errorDisplayDuration is how long an error stays in the status line when
+// nothing else clears it.
+const errorDisplayDuration = 5 * time.Second
+
+type errorExpiredMsg struct {
+	seq int
+}
+
+// showError puts err in the status line until the next key in normal mode
+// or until errorDisplayDuration passes, whichever comes first.
+func (t *TodoList) showError(err error) tea.Cmd {
+	t.lastErr = err
+	t.lastErrSeq++
+	seq := t.lastErrSeq
+	return tea.Tick(errorDisplayDuration, func(time.Time) tea.Msg {
+		return errorExpiredMsg{seq: seq}
+	})
+}
+
+// signalInvalid gives feedback for a key that had nothing to act on: an
+// optional terminal bell and a brief inversion of the header, which every
+// mode shows.
+func (t *TodoList) signalInvalid() tea.Cmd {
+	var cmds []tea.Cmd
+	if t.audibleBell {
+		cmds = append(cmds, ringBell)
+	}
+	if t.visualBell {
+		t.visualBellSeq++
+		t.visualBellOn = true
+		seq := t.visualBellSeq
+		cmds = append(cmds, tea.Tick(visualBellDuration, func(time.Time) tea.Msg {
+			return visualBellExpiredMsg{seq: seq}
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// refuse explains in the status line why a key did nothing, along with the
+// usual invalid-key feedback.
+func (t *TodoList) refuse(reason string) tea.Cmd {
+	t.statusMsg = reason
+	return t.signalInvalid()
+}
+
+// unboundKeyCooldown is how long after one unbound key further ones only
+// update the status line, so mashing keys does not keep ringing the bell.
+const unboundKeyCooldown = 2 * time.Second
+
+// refuseUnbound reports a key that does nothing in normal mode.
+func (t *TodoList) refuseUnbound(key string) tea.Cmd {
+	reason := fmt.Sprintf("%s: key not bound — see the footer for keys", key)
+	now := time.Now()
+	if now.Sub(t.unboundAt) < unboundKeyCooldown {
+		t.statusMsg = reason
+		return nil
+	}
+	t.unboundAt = now
+	return t.refuse(reason)
+}
+
+func ringBell() tea.Msg {
+	os.Stdout.WriteString("\a")
+	return nil
+}
+
+// Read-only accessors
+
+func (t *TodoList) Len() int {
+	return t.items.Len()
+}
+
+func (t *TodoList) At(index int) (TodoItem, bool) {
+	if !t.isValidIndex(index) {
+		return TodoItem{}, false
+	}
+	return t.items.at(index).clone(), true
+}
+
+// Items returns a copy of the list so callers cannot mutate internal state.
+func (t *TodoList) Items() []TodoItem {
+	items := t.items.slice()
+	for i, item := range items {
+		items[i] = item.clone()
+	}
+	return items
+}
+
+// clone copies the item deeply enough that changing the copy never affects
+// the list.
+func (item TodoItem) clone() TodoItem {
+	item.Fields = maps.Clone(item.Fields)
+	item.Tags = slices.Clone(item.Tags)
+	if item.DueDate != nil {
+		due := *item.DueDate
+		item.DueDate = &due
+	}
+	return item
 }
 
 // normal mode
 
 func (t TodoList) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
+	t.statusMsg = ""
+	t.lastErr = nil
+
+	key := msg.String()
+	if t.simpleMode && len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+		if !t.selectByNumber(int(key[0] - '0')) {
+			t.actionMenu = false
+			return t, t.refuse("no item with that number")
+		}
+		t.actionMenu = true
+		return t, nil
+	}
+	t.pendingNumber = 0
+	if t.actionMenu {
+		// The second key of the menu runs as it would in normal mode; esc
+		// only closes the menu.
+		t.actionMenu = false
+		if key == "esc" {
+			return t, nil
+		}
+	}
+	if msg.Paste {
+		return t, t.refuse("press n before pasting a new item")
+	}
+
+	if len(key) == 1 && key[0] >= '0' && key[0] <= '9' && t.addCountDigit(int(key[0]-'0')) {
+		return t, nil
+	}
+	if key == "esc" && t.count > 0 {
+		t.count = 0
+		return t, nil
+	}
+	count := max(t.count, 1)
+	t.count = 0
+
+	switch key {
 	case "q", "esc", "ctrl+c":
+		// esc backs out of every filter before it quits.
+		if key == "esc" && t.filtered() {
+			t.filter = ""
+			t.filterMode = FilterAll
+			t.tagFilter = ""
+			return t, nil
+		}
 		return t, tea.Quit
 
 	case "up", "k":
+		if !t.hasSelection() || len(t.visibleItems()) < 2 {
+			return t, t.refuse("nothing to move to")
+		}
 		t.moveCursor(CursorUp)
 
 	case "down", "j":
+		if !t.hasSelection() || len(t.visibleItems()) < 2 {
+			return t, t.refuse("nothing to move to")
+		}
 		t.moveCursor(CursorDown)
 
+	case "pgdown", "pgup", "ctrl+d", "ctrl+u":
+		pages := 1.0
+		if key == "ctrl+d" || key == "ctrl+u" {
+			pages = 0.5
+		}
+		if key == "pgup" || key == "ctrl+u" {
+			pages = -pages
+		}
+		if !t.hasSelection() {
+			return t, t.refuse("nothing to move to")
+		}
+		if !t.pageCursor(pages) {
+			if pages < 0 {
+				return t, t.refuse("already at the top")
+			}
+			return t, t.refuse("already at the bottom")
+		}
+
+	case "g", "home":
+		visible := t.visibleItems()
+		if len(visible) == 0 {
+			return t, t.refuse("nothing to move to")
+		}
+		if t.selectedIndex == visible[0] {
+			return t, t.refuse("already at the top")
+		}
+		t.selectedIndex = visible[0]
+
+	case "G", "end":
+		visible := t.visibleItems()
+		if len(visible) == 0 {
+			return t, t.refuse("nothing to move to")
+		}
+		if t.selectedIndex == visible[len(visible)-1] {
+			return t, t.refuse("already at the bottom")
+		}
+		t.selectedIndex = visible[len(visible)-1]
+
+	case "shift+up", "K":
+		// Neighbours may be hidden by the filter, so reordering needs the
+		// whole list in view.
+		if t.filtered() {
+			return t, t.refuse("clear the filter to reorder items")
+		}
+		if err := t.MoveItemUp(t.selectedIndex); err != nil {
+			return t, t.refuse("nothing to reorder")
+		}
+
+	case "shift+down", "J":
+		if t.filtered() {
+			return t, t.refuse("clear the filter to reorder items")
+		}
+		if err := t.MoveItemDown(t.selectedIndex); err != nil {
+			return t, t.refuse("nothing to reorder")
+		}
+
+	case "/":
+		t.enterSearchMode()
+
+	case "f":
+		t.filterMode = t.filterMode.next()
+		t.statusMsg = "showing " + t.filterMode.String() + " items"
+
+	case "i":
+		t.showDetails = !t.showDetails
+
+	case "x":
+		if t.store == nil {
+			return t, t.refuse("no data file to export next to")
+		}
+		return t, t.exportCmd(exportPath(t.store.Path()))
+
+	case "t":
+		if !t.cycleTagFilter() {
+			return t, t.refuse("no items have tags")
+		}
+		t.statusMsg = "showing all tags"
+		if t.tagFilter != "" {
+			t.statusMsg = "showing #" + t.tagFilter
+		}
+
 	case "a":
+		if t.items.Len() == 0 {
+			return t, t.refuse("nothing to toggle")
+		}
 		t.ToggleAllItems()
 
 	case "enter", " ":
-		t.ToggleItem(t.selectedIndex)
+		if !t.hasSelection() {
+			return t, t.refuse("nothing to toggle")
+		}
+		if count > 1 {
+			t.toggleCount(count)
+			return t, nil
+		}
+		if err := t.ToggleItem(t.selectedIndex); err != nil {
+			return t, t.refuse("nothing to toggle")
+		}
 
 	case "n":
 		t.enterInputMode(ActionCreate, "")
+		return t, t.offerDraft()
+
+	case "ctrl+p":
+		t.enterPaletteMode()
+
+	case "s":
+		if t.items.Len() < 2 {
+			return t, t.refuse("nothing to sort")
+		}
+		t.SortByDue()
+		t.statusMsg = "sorted by due date"
+
+	case "p":
+		if !t.hasSelection() {
+			return t, t.refuse("nothing to prioritize")
+		}
+		if err := t.CyclePriority(t.selectedIndex); err != nil {
+			return t, t.refuse("nothing to prioritize")
+		}
+		t.statusMsg = "priority: " + t.items.at(t.selectedIndex).Priority.String()
+
+	case "C":
+		if !t.confirmClearCompleted() {
+			return t, t.refuse("no completed items")
+		}
+
+	case "o":
+		cmd, ok := t.openLinks()
+		if !ok {
+			return t, t.refuse("no link to open")
+		}
+		return t, cmd
+
+	case "w":
+		if !t.hasSelection() {
+			return t, t.refuse("nothing to mark waiting")
+		}
+		if err := t.ToggleWaiting(t.selectedIndex); err != nil {
+			return t, t.refuse("nothing to mark waiting")
+		}
+
+	case "P":
+		if t.items.Len() < 2 {
+			return t, t.refuse("nothing to sort")
+		}
+		t.SortByPriority()
+		t.statusMsg = "sorted by priority"
+
+	case "m":
+		return t, t.toggleSimpleMode()
 
 	case "e":
-		if len(t.items) > 0 {
-			t.enterInputMode(ActionEdit, t.items[t.selectedIndex].Title)
+		if !t.hasSelection() {
+			return t, t.refuse("nothing to edit")
 		}
+		t.enterEditMode(t.selectedIndex)
+		return t, t.offerDraft()
 
 	case "d":
-		if len(t.items) > 0 {
-			t.DeleteItem(t.selectedIndex)
+		if !t.hasSelection() {
+			return t, t.refuse("nothing to delete")
 		}
+		if count > 1 {
+			t.deleteCount(count)
+			return t, nil
+		}
+		return t, t.deleteSelected()
+
+	case "u":
+		if err := t.Undo(); err != nil {
+			return t, t.refuse("no undo history")
+		}
+
+	case "ctrl+r":
+		if err := t.Redo(); err != nil {
+			return t, t.refuse("nothing to redo")
+		}
+
+	default:
+		return t, t.refuseUnbound(key)
 	}
 
 	return t, nil
 }
 
+// adjustCursorAfterDelete keeps the cursor at the same position so it lands on
+// the item that shifted into the deleted slot, moving to the new last item
+// when the deleted item was at the end.
 func (t *TodoList) adjustCursorAfterDelete() {
-	if len(t.items) == 0 {
+	if t.items.Len() == 0 {
 		t.selectedIndex = 0
+		return
 	}
-	if t.selectedIndex >= len(t.items) {
-		t.selectedIndex = len(t.items) - 1
+	if t.selectedIndex >= t.items.Len() {
+		t.selectedIndex = t.items.Len() - 1
 	}
 }
 
@@ -132,25 +661,75 @@ const (
 	CursorDown
 )
 
+// moveCursor moves the selection to the previous or next visible item,
+// wrapping at either end.
 func (t *TodoList) moveCursor(direction CursorDirection) {
-	if len(t.items) == 0 {
+	visible := t.visibleItems()
+	if len(visible) == 0 {
 		return
 	}
 
+	pos := visiblePos(visible, t.selectedIndex)
 	switch direction {
 	case CursorUp:
-		if t.selectedIndex > 0 {
-			t.selectedIndex -= 1
+		if pos > 0 {
+			pos -= 1
 		} else {
-			t.selectedIndex = len(t.items) - 1
+			pos = len(visible) - 1
 		}
 	case CursorDown:
-		if t.selectedIndex < len(t.items)-1 {
-			t.selectedIndex += 1
+		if pos < len(visible)-1 {
+			pos += 1
 		} else {
-			t.selectedIndex = 0
+			pos = 0
 		}
 	}
+	t.selectedIndex = visible[pos]
+}
+
+// MoveItem moves the item at index one place in direction and keeps the
+// cursor on it. Like moveCursor it wraps: moving the first item up sends it
+// to the bottom and moving the last item down sends it to the top, leaving
+// the order of everything else unchanged.
+func (t *TodoList) MoveItem(index int, direction CursorDirection) error {
+	if !t.isValidIndex(index) {
+		return &ValidationError{Operation: "move", Err: errors.New("invalid index")}
+	}
+	if t.items.Len() < 2 {
+		return &ValidationError{Operation: "move", Err: errors.New("nothing to move past")}
+	}
+
+	target := index + 1
+	if direction == CursorUp {
+		target = index - 1
+	}
+
+	t.recordUndo()
+	item := t.items.at(index)
+	switch {
+	case target < 0:
+		t.items.remove([]int{index})
+		t.items.append(item)
+		target = t.items.Len() - 1
+	case target >= t.items.Len():
+		t.items.remove([]int{index})
+		t.items.insert(0, item)
+		target = 0
+	default:
+		t.items.set(index, t.items.at(target))
+		t.items.set(target, item)
+	}
+	t.selectedIndex = target
+	t.save()
+	return nil
+}
+
+func (t *TodoList) MoveItemUp(index int) error {
+	return t.MoveItem(index, CursorUp)
+}
+
+func (t *TodoList) MoveItemDown(index int) error {
+	return t.MoveItem(index, CursorDown)
 }
 
 func (t *TodoList) enterInputMode(action InputAction, initialValue string) {
@@ -159,15 +738,112 @@ func (t *TodoList) enterInputMode(action InputAction, initialValue string) {
 		Action:     action,
 		Content:    initialValue,
 		InitialVal: initialValue,
-		Cursor:     len(initialValue),
+		Cursor:     utf8.RuneCountInString(initialValue),
+	}
+}
+
+func (t *TodoList) enterEditMode(index int) {
+	item, ok := t.At(index)
+	if !ok {
+		return
+	}
+	content := item.Title
+	if len(item.Fields) > 0 {
+		content += " " + formatFields(item.Fields)
 	}
+	if len(item.Tags) > 0 {
+		content += " " + formatTags(item.Tags)
+	}
+	if item.Waiting {
+		content += " " + waitingTag
+	}
+	if item.DueDate != nil {
+		content += " @" + item.DueDate.Format(dueLayout)
+	}
+	t.enterInputMode(ActionEdit, content)
+	t.input.Index = index
+	t.input.editing = item
+}
+
+// sameItem reports whether a and b are the same item, possibly changed
+// since: items have no id, so it goes by title and creation time.
+func sameItem(a, b TodoItem) bool {
+	return a.Title == b.Title && a.CreatedAt.Equal(b.CreatedAt)
+}
+
+// editedIndex finds the item the open edit began on. The list can change
+// while an edit is open, so it may have moved from input.Index or be gone.
+func (t *TodoList) editedIndex() (int, bool) {
+	if item, ok := t.At(t.input.Index); ok && sameItem(item, t.input.editing) {
+		return t.input.Index, true
+	}
+	return t.indexOf(t.input.editing)
+}
+
+// indexOf finds target in the list by sameItem.
+func (t *TodoList) indexOf(target TodoItem) (int, bool) {
+	for i, item := range t.items.all() {
+		if sameItem(*item, target) {
+			return i, true
+		}
+	}
+	return 0, false
 }
 
+// AddItem adds an item titled title. A leading ! or !! sets its priority,
+// @waiting marks it as waiting and #tags are taken out as its tags.
 func (t *TodoList) AddItem(title string) error {
-	if err := validateItemTitle(title); err != nil {
+	return t.addItem(newItem(title))
+}
+
+// newItem parses the markers AddItem accepts out of title.
+func newItem(title string) TodoItem {
+	title, waiting := parseWaitingTag(title)
+	title, tags := parseTags(title)
+	title, priority := parsePriorityPrefix(title)
+	return TodoItem{Title: title, Priority: priority, Waiting: waiting, Tags: tags}
+}
+
+func (t *TodoList) addItem(item TodoItem) error {
+	if err := validateItemTitle(item.Title); err != nil {
 		return err
 	}
-	t.items = append(t.items, TodoItem{Title: title})
+	t.recordUndo()
+	t.appendItem(item)
+	return nil
+}
+
+// appendItem adds a validated item to the end of the list without recording
+// an undo step, for callers that record one step for several items.
+func (t *TodoList) appendItem(item TodoItem) {
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now()
+	}
+	t.items.append(item)
+	t.save()
+}
+
+// editItem replaces the title, fields and due date of the item at index with
+// those of edit. Its priority only changes when edit sets one.
+func (t *TodoList) editItem(index int, edit TodoItem) error {
+	if !t.isValidIndex(index) {
+		return &ValidationError{Operation: "edit", Err: errors.New("item no longer exists")}
+	}
+	if err := validateItemTitle(edit.Title); err != nil {
+		return err
+	}
+	t.recordUndo()
+	t.items.update([]int{index}, func(item *TodoItem) {
+		item.Title = edit.Title
+		item.Fields = edit.Fields
+		item.DueDate = edit.DueDate
+		item.Waiting = edit.Waiting
+		item.Tags = edit.Tags
+		if edit.Priority != PriorityNone {
+			item.Priority = edit.Priority
+		}
+	})
+	t.save()
 	return nil
 }
 
@@ -175,99 +851,212 @@ func (t *TodoList) DeleteItem(index int) error {
 	if !t.isValidIndex(index) {
 		return &ValidationError{Operation: "delete", Err: errors.New("invalid index")}
 	}
-	t.items = slices.Delete(t.items, index, index+1)
+	t.recordUndo()
+	t.deleteSeq++
+	t.recentlyDeleted = &DeletedItem{Item: t.items.at(index), seq: t.deleteSeq}
+	t.items.remove([]int{index})
 	t.adjustCursorAfterDelete()
+	t.save()
 	return nil
 }
 
+func expireDeleted(seq int) tea.Cmd {
+	return tea.Tick(deleteRestoreWindow, func(time.Time) tea.Msg {
+		return deleteExpiredMsg{seq: seq}
+	})
+}
+
 func (t *TodoList) ToggleItem(index int) error {
 	if !t.isValidIndex(index) {
 		return &ValidationError{Operation: "toggle", Err: errors.New("invalid index")}
 	}
-	t.items[index].Completed = !t.items[index].Completed
+	t.recordUndo()
+	now := time.Now()
+	t.items.update([]int{index}, func(item *TodoItem) {
+		item.setCompleted(!item.Completed, now)
+		item.Waiting = false
+	})
+	t.save()
 	return nil
 }
 
 func (t *TodoList) ToggleAllItems() {
 	allCompleted := true
-	for _, item := range t.items {
+	for _, item := range t.items.all() {
 		if !item.Completed {
 			allCompleted = false
 			break
 		}
 	}
 
-	for i := range t.items {
-		t.items[i].Completed = !allCompleted
-	}
+	t.recordUndo()
+	now := time.Now()
+	t.items.update(allIndexes(t.items.Len()), func(item *TodoItem) {
+		item.setCompleted(!allCompleted, now)
+		item.Waiting = false
+	})
+	t.save()
 }
 
 func (t TodoList) handleTextInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyUp && t.resumeDraft() {
+		return t, nil
+	}
+	t.draftOffered = false
+
+	if msg.Paste && t.input.Action == ActionCreate {
+		t.pasteInput(string(msg.Runes))
+		return t, nil
+	}
+
 	switch msg.Type {
 	case tea.KeyEnter:
-		t.handleInputSubmission()
+		return t, t.handleInputSubmission()
 
 	case tea.KeyEscape:
+		t.saveDraft()
 		t.exitInputMode()
 
+	case tea.KeyTab:
+		if !t.expandSnippet() {
+			return t, t.signalInvalid()
+		}
+
+	default:
+		t.editInput(msg)
+	}
+	return t, nil
+}
+
+// editInput applies a line-editing key to the input buffer. It is shared by
+// every mode that reads text from the user.
+func (t *TodoList) editInput(msg tea.KeyMsg) {
+	switch msg.Type {
 	case tea.KeyBackspace:
 		t.handleBackSpace()
-		return t, nil
 
 	case tea.KeySpace:
 		t.insertAtCursor(" ")
 	case tea.KeyRunes:
+		if msg.Paste {
+			// Input holds a single line, so pasted lines are joined.
+			t.insertAtCursor(strings.Join(splitPastedLines(string(msg.Runes)), " "))
+			return
+		}
 		t.insertAtCursor(string(msg.Runes))
 
 	case tea.KeyLeft:
-		if t.input.Cursor > 0 {
-			t.input.Cursor--
-		}
+		t.input.Cursor = t.input.prevBoundary()
 
 	case tea.KeyRight:
-		if t.input.Cursor < len(t.input.Content) {
-			t.input.Cursor++
-		}
+		t.input.Cursor = t.input.nextBoundary()
 
 	case tea.KeyCtrlA, tea.KeyHome:
 		t.input.Cursor = 0
 
 	case tea.KeyCtrlE, tea.KeyEnd:
-		t.input.Cursor = len(t.input.Content)
+		t.input.Cursor = utf8.RuneCountInString(t.input.Content)
+
+	case tea.KeyCtrlU:
+		t.deleteToLineStart()
+
+	case tea.KeyCtrlW:
+		t.deleteWordBackward()
 	}
-	return t, nil
 }
 
-func (t *TodoList) handleInputSubmission() (tea.Model, tea.Cmd) {
-	trimmedText := strings.TrimSpace(t.input.Content)
+// parseItemInput reads the item typed into the input: its title and the
+// waiting tag, tags, due date, fields and priority prefix around it. A bad
+// date comes back as dueErr with the token left in the title; bad fields
+// are err and the item is unusable.
+func parseItemInput(text string, now time.Time) (item TodoItem, dueErr, err error) {
+	title, waiting := parseWaitingTag(text)
+	title, tags := parseTags(title)
+	title, due, dueErr := parseDue(title, now)
+	title, fields, err := parseFields(title)
+	if err != nil {
+		return TodoItem{}, dueErr, err
+	}
+	title, priority := parsePriorityPrefix(title)
+	return TodoItem{Title: title, Fields: fields, DueDate: due, Priority: priority, Waiting: waiting, Tags: tags}, dueErr, nil
+}
+
+// takeTokens removes the words of text that take claims and returns the
+// rest as the title. The title is only rebuilt, with single spaces, when a
+// word is claimed, so plain titles keep their spacing. An error from take
+// stops the scan and text comes back unchanged.
+func takeTokens(text string, take func(word string) (bool, error)) (string, error) {
+	words := strings.Fields(text)
+	title := make([]string, 0, len(words))
+	for _, word := range words {
+		taken, err := take(word)
+		if err != nil {
+			return text, err
+		}
+		if !taken {
+			title = append(title, word)
+		}
+	}
+	if len(title) == len(words) {
+		return text, nil
+	}
+	return strings.Join(title, " "), nil
+}
+
+// handleInputSubmission saves the typed item. Errors that stop it from
+// being saved keep the input open so the text can be fixed.
+func (t *TodoList) handleInputSubmission() tea.Cmd {
+	trimmedText := strings.TrimSpace(t.titleRules.Apply(t.input.Content))
 
 	if trimmedText == "" {
-		return t, nil
+		return nil
+	}
+	// A bad date is reported but the item is still saved, with the token
+	// left in the title to fix later.
+	item, dueErr, err := parseItemInput(trimmedText, time.Now())
+	if err != nil {
+		return t.showError(err)
 	}
 	if t.input.Action == ActionCreate {
-		if err := t.AddItem(trimmedText); err != nil {
-			t.lastErr = err
-			return t, nil
+		if err := t.addItem(item); err != nil {
+			return t.showError(err)
 		}
 	}
+	var cmd tea.Cmd
 	if t.input.Action == ActionEdit {
-		t.items[t.selectedIndex].Title = trimmedText
+		index, ok := t.editedIndex()
+		if !ok {
+			index = -1
+		}
+		if err := t.editItem(index, item); err != nil {
+			cmd = t.showError(err)
+		}
+	}
+	if dueErr != nil {
+		cmd = t.showError(dueErr)
 	}
 
+	t.dropDraft()
 	t.exitInputMode()
-	return t, nil
+	return cmd
 }
 
 func (t *TodoList) insertAtCursor(text string) {
-	t.input.Content = t.input.Content[:t.input.Cursor] + text + t.input.Content[t.input.Cursor:]
-	t.input.Cursor += len(text)
+	before, after := t.input.split()
+	t.input.Content = before + text + after
+	t.input.Cursor += utf8.RuneCountInString(text)
 }
 
+// handleBackSpace removes the whole character before the cursor, including
+// any combining marks attached to it.
 func (t *TodoList) handleBackSpace() {
-	if len(t.input.Content) > 0 && t.input.Cursor > 0 {
-		t.input.Content = t.input.Content[:t.input.Cursor-1] + t.input.Content[t.input.Cursor:]
-		t.input.Cursor--
+	if t.input.Cursor == 0 {
+		return
 	}
+	runes := []rune(t.input.Content)
+	start := t.input.prevBoundary()
+	t.input.Content = string(runes[:start]) + string(runes[t.input.Cursor:])
+	t.input.Cursor = start
 }
 
 func (t *TodoList) exitInputMode() {
@@ -278,76 +1067,251 @@ func (t *TodoList) exitInputMode() {
 // Bubble Tea
 
 func (t TodoList) Init() tea.Cmd {
-	return nil
+	var idleCmd tea.Cmd
+	if t.idleLockAfter > 0 {
+		idleCmd = idleTimer(t.idleLockAfter, t.idleSeq)
+	}
+	return tea.Batch(idleCmd, t.pollInbox())
 }
 
+// Update must stay cheap: it only changes in-memory state and returns
+// commands, and anything that does I/O runs inside those commands.
 func (t TodoList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if observeUpdate != nil {
+		defer observeUpdate(msg, time.Now())
+	}
+
+	model, cmd := t.update(msg)
+	next, ok := model.(TodoList)
+	if !ok {
+		return model, cmd
+	}
+	next.scrollToSelection()
+	if next.dirty {
+		save := next.saveCmd()
+		return next, tea.Batch(cmd, save)
+	}
+	return next, cmd
+}
+
+func (t TodoList) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case error:
-		t.lastErr = msg
+		return t, t.showError(msg)
+
+	case exportedMsg:
+		t.statusMsg = "exported to " + msg.path
+		return t, nil
+
+	case errorExpiredMsg:
+		if msg.seq == t.lastErrSeq {
+			t.lastErr = nil
+		}
+		return t, nil
+
+	case deleteExpiredMsg:
+		// A later delete replaces the buffer, so only drop it if it is still ours.
+		if t.recentlyDeleted != nil && t.recentlyDeleted.seq == msg.seq {
+			t.recentlyDeleted = nil
+		}
+		return t, nil
+
+	case visualBellExpiredMsg:
+		if msg.seq == t.visualBellSeq {
+			t.visualBellOn = false
+		}
+		return t, nil
+
+	case tea.WindowSizeMsg:
+		t.width = msg.Width
+		t.height = msg.Height
+		return t, nil
+
+	case draftOfferExpiredMsg:
+		if msg.seq == t.draftOfferSeq {
+			t.draftOffered = false
+		}
+		return t, nil
+
+	case idleMsg:
+		if msg.seq == t.idleSeq {
+			t.lock()
+		}
+		return t, nil
+
+	case inboxMsg:
+		t.applyInbox(msg)
+		return t, t.pollInbox()
+
+	case inboxSavedMsg:
+		t.inboxApplied = slices.DeleteFunc(t.inboxApplied, func(file string) bool {
+			return slices.Contains(msg.files, file)
+		})
+		if msg.err != nil {
+			return t, t.showError(fmt.Errorf("inbox: %w", msg.err))
+		}
 		return t, nil
 
 	case tea.KeyMsg:
-		switch t.currentMode {
-		case ModeInput:
-			return t.handleTextInputMode(msg)
-		default:
-			return t.handleNormalMode(msg)
+		idleCmd := t.resetIdleTimer()
+		model, cmd := t.handleKey(msg)
+		if next, ok := model.(TodoList); ok {
+			next.keepSelectionVisible()
+			model = next
 		}
+		return model, tea.Batch(cmd, idleCmd)
+
+	case tea.MouseMsg:
+		idleCmd := t.resetIdleTimer()
+		model, cmd := t.handleMouse(msg)
+		return model, tea.Batch(cmd, idleCmd)
 	}
 	return t, nil
 }
 
-func (t TodoList) View() string {
-	if t.lastErr != nil {
-		return fmt.Sprintf("Error: %v\nPress q to quit.\n", t.lastErr)
+func (t TodoList) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch t.currentMode {
+	case ModeInput:
+		return t.handleTextInputMode(msg)
+	case ModePalette:
+		return t.handlePaletteMode(msg)
+	case ModeLocked:
+		return t.handleLockedMode(msg)
+	case ModeSearch:
+		return t.handleSearchMode(msg)
+	case ModeConfirm:
+		return t.handleConfirmMode(msg)
+	case ModeLinks:
+		return t.handleLinksMode(msg)
+	case ModePaste:
+		return t.handlePasteMode(msg)
+	default:
+		return t.handleNormalMode(msg)
 	}
+}
 
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("you have %d items on your list:\n\n", len(t.items)))
+func (t TodoList) View() string {
+	return t.layout()
+}
 
-	for i, item := range t.items {
-		cursor := " "
-		if t.selectedIndex == i {
-			cursor = ">"
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "backup" {
+		if err := runBackup(os.Args[2:]); err != nil {
+			exitWithError(err)
 		}
-
-		checked := " "
-		if item.Completed {
-			checked = "x"
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			exitWithError(err)
 		}
+		return
+	}
 
-		sb.WriteString(fmt.Sprintf("%s [%s] %s\n", cursor, checked, item.Title))
+	// Parse errors go through exitWithError like every other failure, so
+	// the flag package neither prints nor exits itself.
+	flag.CommandLine.Init("lazylist", flag.ContinueOnError)
+	flag.CommandLine.SetOutput(io.Discard)
+	flag.CommandLine.Usage = func() {}
+	bell := flag.Bool("bell", false, "ring the terminal bell on invalid actions")
+	visualBell := flag.Bool("visual-bell", true, "flash the header on invalid actions")
+	var rules TitleRules
+	flag.BoolVar(&rules.Capitalize, "capitalize", false, "capitalize the first letter of new titles")
+	flag.BoolVar(&rules.CollapseSpaces, "collapse-spaces", false, "collapse runs of whitespace in new titles")
+	flag.BoolVar(&rules.StripTrailing, "strip-punctuation", false, "strip trailing punctuation from new titles")
+	idleLock := flag.Duration("idle-lock", 0, "lock the list after this long without input (0 disables); set LAZYLIST_LOCK_PHRASE to require a passphrase")
+	simple := flag.Bool("simple", false, "start in simple mode: numbered rows, fewer keys, no alternate screen")
+	flag.BoolVar(&displayWidth.EastAsianWidth, "ambiguous-wide", false, "treat East Asian ambiguous-width characters as two cells wide")
+	modeLine := flag.Bool("mode-line", false, "show the current mode in the footer")
+	confirmDelete := flag.Bool("confirm-delete", true, "ask before d deletes an item or C clears completed items")
+	mouse := flag.Bool("mouse", true, "click to select and toggle items and scroll with the wheel (not in simple mode)")
+	file := flag.String("file", "", "open the list in `path` instead of the default data file")
+	add := flag.String("add", "", "add an item titled `title` to the list and exit; queued for a running lazylist if one has the list open")
+	renderFramePath := flag.String("render-frame", "", "render one frame of the model state in `file` to stdout and exit")
+	if err := flag.CommandLine.Parse(os.Args[1:]); errors.Is(err, flag.ErrHelp) {
+		printUsage(os.Stderr)
+		return
+	} else if err != nil {
+		exitWithError(&UsageError{Err: err})
 	}
 
-	sb.WriteString("\n")
+	if *renderFramePath != "" {
+		if err := renderFrame(*renderFramePath, os.Stdout); err != nil {
+			exitWithError(err)
+		}
+		return
+	}
 
-	if t.currentMode == ModeInput {
-		actionText := "edit item"
-		if t.input.Action == ActionCreate {
-			actionText = "enter new item"
+	path, err := resolveStorePath(*file, flag.Args())
+	if err != nil {
+		exitWithError(err)
+	}
+	if err := ensureStoreFile(path); err != nil {
+		exitWithError(err)
+	}
+
+	if *add != "" {
+		queued, err := addFromCLI(path, *add)
+		if err != nil {
+			exitWithError(err)
+		}
+		if queued {
+			fmt.Printf("queued '%s' for the open list\n", *add)
 		}
-		sb.WriteString(fmt.Sprintf("%s (esc to cancel):\n", actionText))
-		sb.WriteString(fmt.Sprintf("%s %s|%s\n", ">", t.input.Content[:t.input.Cursor], t.input.Content[t.input.Cursor:]))
+		return
+	}
+
+	release, err := acquireLock(path)
+	if err != nil {
+		exitWithError(err)
 	}
-	if t.currentMode == ModeNormal {
-		sb.WriteString("up/down: move cursor, enter/space: toggle, a: toggle all, n: new item, e: edit, d: delete, q/esc: quit")
+	store := NewStore(path)
+	items, status, err := loadList(store)
+	if err != nil {
+		release()
+		exitWithError(err)
 	}
 
-	return sb.String()
-}
+	list := NewTodoList(items)
+	list.store = store
+	list.statusMsg = status
+	list.audibleBell = *bell
+	list.visualBell = *visualBell
+	list.titleRules = rules
+	list.idleLockAfter = *idleLock
+	list.lockPhrase = os.Getenv("LAZYLIST_LOCK_PHRASE")
+	list.simpleMode = *simple
+	list.mouse = *mouse
+	list.showModeLine = *modeLine
+	list.confirmDelete = *confirmDelete
+
+	// The alternate screen flickers on flaky remote connections, so simple
+	// mode renders inline instead.
+	var opts []tea.ProgramOption
+	if !*simple {
+		opts = append(opts, tea.WithAltScreen())
+		// Inline rendering does not start at the top of the screen, so
+		// clicks cannot be mapped to rows in simple mode.
+		if *mouse {
+			opts = append(opts, tea.WithMouseCellMotion())
+		}
+	}
 
-func main() {
-	initialItems := []string{
-		"Warm eba",
-		"Exercise",
-		"Read a book",
-		"Write some slop",
+	p := tea.NewProgram(list, opts...)
+	model, err := p.Run()
+	if err != nil {
+		release()
+		exitWithError(fmt.Errorf("running programme: %w", err))
 	}
 
-	p := tea.NewProgram(NewTodoList(initialItems), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error running programme: %v\n", err)
-		os.Exit(1)
+	// A background save may still be pending when the program quits, so
+	// write the final state before exiting.
+	if final, ok := model.(TodoList); ok {
+		if err := store.SaveIfNewer(final.saveSeq+1, final.items.slice()); err != nil {
+			release()
+			exitWithError(err)
+		}
+		removeInboxFiles(final.inboxApplied)
 	}
+	release()
 }
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// namedKeys are the keys tests refer to by name, as tea.KeyMsg.String
+// reports them.
+var namedKeys = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEscape,
+	"tab":       tea.KeyTab,
+	"backspace": tea.KeyBackspace,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	"shift+up":  tea.KeyShiftUp,
+	"ctrl+a":    tea.KeyCtrlA,
+	"ctrl+c":    tea.KeyCtrlC,
+	"ctrl+d":    tea.KeyCtrlD,
+	"ctrl+e":    tea.KeyCtrlE,
+	"ctrl+p":    tea.KeyCtrlP,
+	"ctrl+r":    tea.KeyCtrlR,
+	"ctrl+u":    tea.KeyCtrlU,
+	"ctrl+w":    tea.KeyCtrlW,
+}
+
+// keyMsg builds the message the terminal sends for key: a name from
+// namedKeys, a space, or literal runes.
+func keyMsg(key string) tea.KeyMsg {
+	if keyType, ok := namedKeys[key]; ok {
+		return tea.KeyMsg{Type: keyType}
+	}
+	if key == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// send delivers msg and returns the updated list with the command it
+// returned.
+func send(t *testing.T, list TodoList, msg tea.Msg) (TodoList, tea.Cmd) {
+	t.Helper()
+	model, cmd := list.Update(msg)
+	next, ok := model.(TodoList)
+	if !ok {
+		t.Fatalf("Update returned %T, want TodoList", model)
+	}
+	return next, cmd
+}
+
+// press sends each key in turn, discarding the commands they return.
+func press(t *testing.T, list TodoList, keys ...string) TodoList {
+	t.Helper()
+	for _, key := range keys {
+		list, _ = send(t, list, keyMsg(key))
+	}
+	return list
+}
+
+// typeText types text one character at a time.
+func typeText(t *testing.T, list TodoList, text string) TodoList {
+	t.Helper()
+	for _, r := range text {
+		list = press(t, list, string(r))
+	}
+	return list
+}
+
+// newTestList returns a list holding titles in an 80x24 terminal.
+func newTestList(t *testing.T, titles ...string) TodoList {
+	t.Helper()
+	items := make([]TodoItem, len(titles))
+	for i, title := range titles {
+		items[i] = TodoItem{Title: title}
+	}
+	list, _ := send(t, *NewTodoList(items), tea.WindowSizeMsg{Width: 80, Height: 24})
+	return list
+}
+
+// titles lists the item titles in order.
+func titles(list TodoList) []string {
+	var out []string
+	for _, item := range list.Items() {
+		out = append(out, item.Title)
+	}
+	return out
+}
+
+// assertTitles fails unless the list holds exactly want, in order.
+func assertTitles(t *testing.T, list TodoList, want ...string) {
+	t.Helper()
+	if got := titles(list); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("titles = %q, want %q", got, want)
+	}
+}
+
+func TestDeleteKeepsCursorInPlace(t *testing.T) {
+	tests := []struct {
+		name   string
+		titles []string
+		moves  []string
+		want   []string
+		cursor int
+	}{
+		{"middle", []string{"a", "b", "c", "d", "e"}, []string{"j"}, []string{"a", "c", "d", "e"}, 1},
+		{"last", []string{"a", "b", "c"}, []string{"G"}, []string{"a", "b"}, 1},
+		{"only", []string{"a"}, nil, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := newTestList(t, tt.titles...)
+			list.confirmDelete = false
+			list = press(t, press(t, list, tt.moves...), "d")
+			assertTitles(t, list, tt.want...)
+			if list.selectedIndex != tt.cursor {
+				t.Errorf("cursor on %d, want %d", list.selectedIndex, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestJumpToTopAndBottom(t *testing.T) {
+	list := newTestList(t, "a", "b", "c", "d")
+	if list = press(t, list, "G"); list.selectedIndex != 3 {
+		t.Errorf("G: cursor on %d, want 3", list.selectedIndex)
+	}
+	if list = press(t, list, "g"); list.selectedIndex != 0 {
+		t.Errorf("g: cursor on %d, want 0", list.selectedIndex)
+	}
+
+	empty := newTestList(t)
+	for _, key := range []string{"g", "G"} {
+		list := press(t, empty, key)
+		if list.selectedIndex != 0 || list.statusMsg != "nothing to move to" {
+			t.Errorf("%s on an empty list: cursor %d, status %q", key, list.selectedIndex, list.statusMsg)
+		}
+	}
+}
+
+// TestParseItemInputTitleSpacing checks that tokens taken out of a title
+// leave single spaces behind, and that a title without tokens keeps its
+// spacing as typed.
+func TestParseItemInputTitleSpacing(t *testing.T) {
+	now := time.Date(2030, time.May, 17, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		typed, title string
+	}{
+		{"call  the   bank", "call  the   bank"},
+		{"call  the bank  @waiting", "call the bank"},
+		{"call  #work the bank", "call the bank"},
+		{"call  phone:555  the bank", "call the bank"},
+		{"call  due:today  the bank", "call the bank"},
+		{"call  due:someday  the bank", "call  due:someday  the bank"},
+	}
+	for _, tt := range tests {
+		item, _, err := parseItemInput(tt.typed, now)
+		if err != nil {
+			t.Fatalf("parseItemInput(%q): %v", tt.typed, err)
+		}
+		if item.Title != tt.title {
+			t.Errorf("parseItemInput(%q) title = %q, want %q", tt.typed, item.Title, tt.title)
+		}
+	}
+}
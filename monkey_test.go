@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A monkey test plays random sessions of legal keys against the model and
+// checks invariants that must hold between any two of them. A failing
+// session is cut down to the fewest steps that still fail, which are
+// printed for reproduction.
+
+// monkeyKeys are the keys a session draws from: every binding of every
+// mode, a few characters to type, and some that are bound nowhere.
+var monkeyKeys = slices.Concat(normalModeKeys, []string{
+	"tab", "backspace", "left", "right", "ctrl+a", "ctrl+e", "ctrl+w",
+	"y", "Y", "N", "1", "2", "#", "!",
+})
+
+// monkeyStep is one input of a session: a key, a paste, a resize or the
+// idle timer firing.
+type monkeyStep struct {
+	key           string
+	paste         string
+	width, height int
+	idle          bool
+}
+
+func (s monkeyStep) String() string {
+	switch {
+	case s.paste != "":
+		return fmt.Sprintf("paste(%q)", s.paste)
+	case s.width > 0:
+		return fmt.Sprintf("resize(%dx%d)", s.width, s.height)
+	case s.idle:
+		return "idle"
+	}
+	return fmt.Sprintf("%q", s.key)
+}
+
+// msg builds the message for s as list would receive it next.
+func (s monkeyStep) msg(list TodoList) tea.Msg {
+	switch {
+	case s.paste != "":
+		return pasteMsg(s.paste)
+	case s.width > 0:
+		return tea.WindowSizeMsg{Width: s.width, Height: s.height}
+	case s.idle:
+		return idleMsg{seq: list.idleSeq}
+	}
+	return keyMsg(s.key)
+}
+
+func randomStep(rng *rand.Rand) monkeyStep {
+	switch n := rng.IntN(100); {
+	case n < 2:
+		return monkeyStep{paste: []string{"one\ntwo", "three", "a\n\nb\nc"}[rng.IntN(3)]}
+	case n < 4:
+		return monkeyStep{width: 10 + rng.IntN(120), height: 3 + rng.IntN(40)}
+	case n < 5:
+		return monkeyStep{idle: true}
+	}
+	return monkeyStep{key: monkeyKeys[rng.IntN(len(monkeyKeys))]}
+}
+
+// countChanges reports whether a key may add or remove items in mode.
+func countChanges(mode AppMode, key string) bool {
+	switch mode {
+	case ModeInput:
+		return key == "enter"
+	case ModePaste:
+		return key == "s"
+	case ModeConfirm:
+		return key == "y" || key == "Y"
+	case ModeNormal:
+		return key == "d" || key == "C" || key == "u" || key == "ctrl+r"
+	}
+	return false
+}
+
+// newMonkeyList is the list every session starts from. Half the sessions
+// delete without asking first.
+func newMonkeyList(confirmDelete bool) TodoList {
+	items := []TodoItem{
+		{Title: "buy milk #home"},
+		{Title: "read https://a.example and https://b.example", Completed: true},
+		{Title: "call the bank about the long overdue statement", Tags: []string{"admin"}},
+		{Title: "water plants", Completed: true},
+	}
+	list := NewTodoList(items)
+	list.idleLockAfter = 1
+	list.confirmDelete = confirmDelete
+	model, _ := list.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	return model.(TodoList)
+}
+
+// playSession runs steps and returns how many ran before an invariant
+// broke, with what broke, or len(steps) and "" when all held.
+func playSession(confirmDelete bool, steps []monkeyStep) (n int, failure string) {
+	list := newMonkeyList(confirmDelete)
+	defer func() {
+		if r := recover(); r != nil {
+			failure = fmt.Sprintf("panic: %v", r)
+		}
+	}()
+	for n = range steps {
+		step := steps[n]
+		before, mode := list.Len(), list.currentMode
+		model, _ := list.Update(step.msg(list))
+		list = model.(TodoList)
+		if failure = checkInvariants(list); failure != "" {
+			return n + 1, failure
+		}
+		if list.Len() != before && (step.paste != "" || !countChanges(mode, step.key)) {
+			return n + 1, fmt.Sprintf("item count went from %d to %d in %s mode", before, list.Len(), modes[mode].name)
+		}
+	}
+	return len(steps), ""
+}
+
+func checkInvariants(list TodoList) string {
+	if _, ok := modes[list.currentMode]; !ok {
+		return fmt.Sprintf("unknown mode %d", list.currentMode)
+	}
+	if list.Len() == 0 && list.selectedIndex != 0 || list.Len() > 0 && (list.selectedIndex < 0 || list.selectedIndex >= list.Len()) {
+		return fmt.Sprintf("cursor on %d of %d items", list.selectedIndex, list.Len())
+	}
+	switch list.currentMode {
+	case ModeNormal, ModeConfirm, ModeLinks:
+		if !reflect.DeepEqual(list.input, InputContext{}) {
+			return fmt.Sprintf("input %q left over in %s mode", list.input.Content, modes[list.currentMode].name)
+		}
+	}
+	if lines := strings.Count(list.View(), "\n") + 1; list.height > 0 && lines > list.height {
+		return fmt.Sprintf("view is %d lines in a %d-line terminal", lines, list.height)
+	}
+	return ""
+}
+
+// minimizeSession drops steps from a failing session for as long as it
+// still fails, halving the chunk dropped each round.
+func minimizeSession(confirmDelete bool, steps []monkeyStep) []monkeyStep {
+	for chunk := max(len(steps)/2, 1); chunk >= 1; chunk /= 2 {
+		for i := 0; i+chunk <= len(steps); {
+			candidate := slices.Delete(slices.Clone(steps), i, i+chunk)
+			if _, failure := playSession(confirmDelete, candidate); failure != "" {
+				steps = candidate
+			} else {
+				i += chunk
+			}
+		}
+	}
+	return steps
+}
+
+func TestMonkeySessions(t *testing.T) {
+	sessions, length := 200, 300
+	if testing.Short() || raceEnabled {
+		sessions = 20
+	}
+	for seed := range sessions {
+		rng := rand.New(rand.NewPCG(uint64(seed), 257))
+		steps := make([]monkeyStep, length)
+		for i := range steps {
+			steps[i] = randomStep(rng)
+		}
+		confirmDelete := seed%2 == 0
+		n, failure := playSession(confirmDelete, steps)
+		if failure == "" {
+			continue
+		}
+		steps = minimizeSession(confirmDelete, steps[:n])
+		_, failure = playSession(confirmDelete, steps)
+		t.Fatalf("session %d (confirm delete %v): %s after %d steps:\n%v", seed, confirmDelete, failure, len(steps), steps)
+	}
+}
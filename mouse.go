@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Mouse support is for normal mode: a left click selects a row, a click on
+// its checkbox toggles it, and the wheel moves the cursor. Clicks anywhere
+// else are ignored.
+
+// wheelStep is how many rows one wheel notch moves the cursor.
+const wheelStep = 1
+
+func (t TodoList) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if t.currentMode != ModeNormal {
+		return t, nil
+	}
+
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		t.moveCursorBy(-wheelStep)
+
+	case msg.Button == tea.MouseButtonWheelDown:
+		t.moveCursorBy(wheelStep)
+
+	case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+		index, ok := t.itemAt(msg.Y)
+		if !ok {
+			return t, nil
+		}
+		t.statusMsg = ""
+		t.selectedIndex = index
+		if start := t.checkboxColumn(); msg.X >= start && msg.X < start+len("[ ]") {
+			if err := t.ToggleItem(index); err != nil {
+				return t, t.signalInvalid()
+			}
+		}
+	}
+	return t, nil
+}
+
+// itemAt maps a screen row to the item drawn on it, allowing for the lines
+// above the list as the layout leaves them, the scroll indicator, the detail
+// line and simple mode's blank lines.
+func (t TodoList) itemAt(y int) (int, bool) {
+	visible := t.visibleItems()
+	start, end := t.visibleRange(t.listHeight())
+
+	above, _, _ := t.frame()
+	line := len(above)
+	if end-start < len(visible) {
+		line++
+	}
+	for pos := start; pos < end; pos++ {
+		if y == line {
+			return visible[pos], true
+		}
+		line += t.rowHeight()
+		if visible[pos] == t.selectedIndex {
+			line += t.detailRows()
+		}
+	}
+	return 0, false
+}
+
+// checkboxColumn is the screen column where row checkboxes start, after the
+// cursor and, in simple mode, the row number.
+func (t TodoList) checkboxColumn() int {
+	column := len("> ")
+	if t.simpleMode {
+		column += len(strconv.Itoa(len(t.visibleItems()))) + len(". ")
+	}
+	return column
+}
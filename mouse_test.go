@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestClickTogglesRowUnderPointer clicks the checkbox column of every line
+// of the frame and checks the item toggled is the one drawn there,
+// including on terminals short enough that the layout drops the header.
+func TestClickTogglesRowUnderPointer(t *testing.T) {
+	for _, simple := range []bool{false, true} {
+		for height := 3; height <= 12; height++ {
+			list := newTestList(t, "item a", "item b", "item c", "item d", "item e")
+			list.simpleMode = simple
+			list, _ = send(t, list, tea.WindowSizeMsg{Width: 80, Height: height})
+
+			for y, line := range strings.Split(list.View(), "\n") {
+				want := -1
+				for i := range list.items.Len() {
+					if strings.Contains(line, fmt.Sprintf("item %c", 'a'+i)) {
+						want = i
+					}
+				}
+				click := tea.MouseMsg{X: list.checkboxColumn() + 1, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress}
+				clicked, _ := send(t, list, click)
+				toggled := -1
+				for i, item := range clicked.Items() {
+					if item.Completed {
+						toggled = i
+					}
+				}
+				if toggled != want {
+					t.Errorf("simple %v, height %d: click on line %d %q toggles item %d, want %d", simple, height, y, line, toggled, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TitleRules are optional clean-ups applied to titles typed in input mode.
+// Titles that were never edited are left exactly as they were.
+type TitleRules struct {
+	Capitalize     bool
+	CollapseSpaces bool
+	StripTrailing  bool
+}
+
+// trailingPunctuation is what StripTrailing removes from the end of a title.
+const trailingPunctuation = ".,;:!?…"
+
+func (r TitleRules) Apply(title string) string {
+	if r.CollapseSpaces {
+		title = strings.Join(strings.Fields(title), " ")
+	}
+	if r.StripTrailing {
+		title = strings.TrimRightFunc(strings.TrimRight(title, trailingPunctuation), unicode.IsSpace)
+	}
+	if r.Capitalize {
+		title = capitalizeFirstWord(title)
+	}
+	return title
+}
+
+// capitalizeFirstWord uppercases the first letter of the title, skipping any
+// leading symbols such as emoji. A letter inside a word ("3rd") is left alone.
+func capitalizeFirstWord(title string) string {
+	prev := ' '
+	for i, r := range title {
+		if unicode.IsLetter(r) {
+			if unicode.IsLetter(prev) || unicode.IsDigit(prev) {
+				return title
+			}
+			return title[:i] + string(unicode.ToTitle(r)) + title[i+len(string(r)):]
+		}
+		prev = r
+	}
+	return title
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteLimit is the number of matches shown in the go-to palette.
+const paletteLimit = 8
+
+type paletteMatch struct {
+	index int
+	score int
+}
+
+// fuzzyScore reports whether every rune of query appears in title in order,
+// ignoring case, and scores the match so that consecutive runs and matches at
+// the start of words rank higher.
+func fuzzyScore(query, title string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return 0, true
+	}
+
+	score, qi, prev := 0, 0, -2
+	runes := []rune(strings.ToLower(title))
+	for i, r := range runes {
+		if qi == len(q) {
+			break
+		}
+		if r != q[qi] {
+			continue
+		}
+
+		score++
+		if i == prev+1 {
+			score += 3
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 2
+		}
+		prev = i
+		qi++
+	}
+
+	if qi < len(q) {
+		return 0, false
+	}
+	// Prefer shorter titles when everything else is equal.
+	return score*100 - len(runes), true
+}
+
+// paletteMatches ranks the visible items against the palette query, best
+// first. Equal scores keep list order. A field:key=value query instead lists
+// the items whose custom field matches exactly. Items the filters hide are
+// not offered, so a jump never has to change the filters to show its
+// target.
+func (t *TodoList) paletteMatches() []paletteMatch {
+	var matches []paletteMatch
+	visible := t.visibleItems()
+	if key, value, ok := parseFieldQuery(t.input.Content); ok {
+		for _, i := range visible {
+			if item := t.items.at(i); item.hasField(key, value) {
+				matches = append(matches, paletteMatch{index: i})
+			}
+		}
+		return matches[:min(len(matches), paletteLimit)]
+	}
+
+	for _, i := range visible {
+		if score, ok := fuzzyScore(t.input.Content, t.items.at(i).Title); ok {
+			matches = append(matches, paletteMatch{index: i, score: score})
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b paletteMatch) int {
+		return b.score - a.score
+	})
+	if len(matches) > paletteLimit {
+		matches = matches[:paletteLimit]
+	}
+	return matches
+}
+
+func (t *TodoList) enterPaletteMode() {
+	t.currentMode = ModePalette
+	t.input = InputContext{}
+	t.paletteCursor = 0
+}
+
+func (t *TodoList) exitPaletteMode() {
+	t.currentMode = ModeNormal
+	t.input = InputContext{}
+	t.paletteCursor = 0
+}
+
+func (t TodoList) handlePaletteMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	matches := t.paletteMatches()
+
+	switch msg.Type {
+	case tea.KeyEscape:
+		t.exitPaletteMode()
+
+	case tea.KeyEnter:
+		if len(matches) > 0 {
+			t.selectedIndex = matches[t.paletteCursor].index
+		}
+		t.exitPaletteMode()
+
+	case tea.KeyUp, tea.KeyCtrlK:
+		if t.paletteCursor > 0 {
+			t.paletteCursor--
+		}
+
+	case tea.KeyDown, tea.KeyCtrlJ:
+		if t.paletteCursor < len(matches)-1 {
+			t.paletteCursor++
+		}
+
+	default:
+		t.editInput(msg)
+		t.paletteCursor = 0
+	}
+	return t, nil
+}
+
+func (t TodoList) paletteLines(width int) []string {
+	lines := []string{
+		truncateToWidth("go to item (esc to cancel):", width),
+		t.inputLine(width),
+	}
+
+	matches := t.paletteMatches()
+	if len(matches) == 0 {
+		lines = append(lines, "  no matches")
+	}
+	for i, match := range matches {
+		cursor := " "
+		if i == t.paletteCursor {
+			cursor = ">"
+		}
+		lines = append(lines, truncateToWidth(fmt.Sprintf("%s %s", cursor, t.items.at(match.index).Title), width))
+	}
+	return lines
+}
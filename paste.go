@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Pasting several lines while creating an item asks whether each line
+// should become its own item. The terminal's bracketed paste delivers the
+// whole block as one key message, which is how a paste is told apart from
+// typing.
+
+// splitPastedLines splits a pasted block into its non-blank lines,
+// accepting any line ending.
+func splitPastedLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// pasteInput inserts pasted text into the new item being typed, offering
+// to split it first when it holds more than one line.
+func (t *TodoList) pasteInput(text string) {
+	lines := splitPastedLines(text)
+	if len(lines) < 2 {
+		t.insertAtCursor(strings.Join(lines, " "))
+		return
+	}
+	t.paste = text
+	t.currentMode = ModePaste
+}
+
+// pastedItems is the lines the typed text would split into with the paste
+// inserted at the cursor.
+func (t TodoList) pastedItems() []string {
+	before, after := t.input.split()
+	return splitPastedLines(before + t.paste + after)
+}
+
+func (t TodoList) handlePasteMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "s":
+		t.splitPaste()
+
+	case "o":
+		t.insertAtCursor(strings.Join(splitPastedLines(t.paste), " "))
+		t.paste = ""
+		t.currentMode = ModeInput
+
+	case "esc":
+		t.paste = ""
+		t.currentMode = ModeInput
+
+	case "ctrl+c":
+		return t, tea.Quit
+
+	default:
+		return t, t.signalInvalid()
+	}
+	return t, nil
+}
+
+// splitPaste adds each of pastedItems as its own item, in order, and
+// leaves input mode. Lines that fail validation are skipped and counted.
+// The whole paste is undone in one step.
+func (t *TodoList) splitPaste() {
+	lines := t.pastedItems()
+
+	undo := t.snapshot()
+	added, rejected := 0, 0
+	for _, line := range lines {
+		item := newItem(strings.TrimSpace(t.titleRules.Apply(line)))
+		if validateItemTitle(item.Title) != nil {
+			rejected++
+			continue
+		}
+		t.appendItem(item)
+		added++
+	}
+	if added > 0 {
+		t.pushUndo(undo)
+	}
+
+	t.statusMsg = fmt.Sprintf("added %d items", added)
+	if rejected > 0 {
+		t.statusMsg += fmt.Sprintf(", %d rejected", rejected)
+	}
+	t.paste = ""
+	t.dropDraft()
+	t.exitInputMode()
+}
+
+func (t TodoList) pasteLines(width int) []string {
+	count := len(t.pastedItems())
+	return []string{
+		truncateToWidth(fmt.Sprintf("pasted %d lines:", count), width),
+		truncateToWidth(fmt.Sprintf("  s: paste as %d separate items", count), width),
+		truncateToWidth("  o: paste as one item (newlines → spaces)", width),
+		truncateToWidth("  esc: cancel", width),
+	}
+}
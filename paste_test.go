@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func pasteMsg(text string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text), Paste: true}
+}
+
+func TestSplitPasteAddsEachLine(t *testing.T) {
+	list := press(t, newTestList(t, "existing"), "n")
+	list = typeText(t, list, "first ")
+	list, _ = send(t, list, pasteMsg("one\r\n\r\ntwo\n!!\nthree"))
+	if list.currentMode != ModePaste {
+		t.Fatalf("mode = %v, want ModePaste", list.currentMode)
+	}
+
+	list = press(t, list, "s")
+	if list.currentMode != ModeNormal {
+		t.Fatalf("mode = %v after split, want ModeNormal", list.currentMode)
+	}
+	assertTitles(t, list, "existing", "first one", "two", "three")
+	if want := "added 3 items, 1 rejected"; list.statusMsg != want {
+		t.Errorf("status = %q, want %q", list.statusMsg, want)
+	}
+
+	list = press(t, list, "u")
+	assertTitles(t, list, "existing")
+}
+
+func TestSplitPasteLongerThanUndoHistory(t *testing.T) {
+	lines := make([]string, maxUndo+50)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	list := press(t, newTestList(t), "n")
+	list, _ = send(t, list, pasteMsg(strings.Join(lines, "\n")))
+	list = press(t, list, "s")
+
+	if got := list.Len(); got != len(lines) {
+		t.Fatalf("Len = %d, want %d", got, len(lines))
+	}
+	if got := len(list.undoStack); got != 1 {
+		t.Errorf("undo steps = %d, want 1", got)
+	}
+	list = press(t, list, "u")
+	if got := list.Len(); got != 0 {
+		t.Errorf("Len after undo = %d, want 0", got)
+	}
+}
+
+func TestPasteAsOneItem(t *testing.T) {
+	list := press(t, newTestList(t), "n")
+	list, _ = send(t, list, pasteMsg("buy\nmilk"))
+	list = press(t, list, "o")
+	if list.currentMode != ModeInput || list.input.Content != "buy milk" {
+		t.Fatalf("mode %v, input %q; want input mode holding %q", list.currentMode, list.input.Content, "buy milk")
+	}
+	list = press(t, list, "enter")
+	assertTitles(t, list, "buy milk")
+}
+
+func TestPasteCancelKeepsInput(t *testing.T) {
+	list := press(t, newTestList(t), "n")
+	list = typeText(t, list, "draft")
+	list, _ = send(t, list, pasteMsg("a\nb"))
+	list = press(t, list, "esc")
+	if list.currentMode != ModeInput || list.input.Content != "draft" {
+		t.Fatalf("mode %v, input %q; want input mode holding %q", list.currentMode, list.input.Content, "draft")
+	}
+	if list.Len() != 0 {
+		t.Errorf("Len = %d, want 0", list.Len())
+	}
+}
+
+func TestPasteJoinsLinesOutsideCreate(t *testing.T) {
+	for _, key := range []string{"e", "/", "ctrl+p"} {
+		list := press(t, newTestList(t, "buy"), key, "end")
+		list, _ = send(t, list, pasteMsg(" milk\r\n\nand bread\n"))
+		if strings.ContainsAny(list.input.Content, "\r\n") {
+			t.Errorf("%s: input holds %q", key, list.input.Content)
+		}
+	}
+	list := press(t, newTestList(t, "buy"), "e", "end")
+	list, _ = send(t, list, pasteMsg(" milk\nand bread"))
+	if want := "buy milk and bread"; list.input.Content != want {
+		t.Errorf("edit input = %q, want %q", list.input.Content, want)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type Priority int
+
+const (
+	// PriorityNone is the zero value so existing items stay unprioritized.
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+var priorityNames = map[Priority]string{
+	PriorityNone:   "none",
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+}
+
+// priorityMarkers are shown before the title. Medium and high match the !
+// and !! prefixes that set them; low is typed as !-.
+var priorityMarkers = map[Priority]string{
+	PriorityLow:    dimStyle.Render("↓"),
+	PriorityMedium: lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Render("!"),
+	PriorityHigh:   lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true).Render("!!"),
+}
+
+func (p Priority) String() string {
+	return priorityNames[p]
+}
+
+// MarshalText stores priorities by name so the data file stays readable.
+func (p Priority) MarshalText() ([]byte, error) {
+	name, ok := priorityNames[p]
+	if !ok {
+		return nil, fmt.Errorf("unknown priority %d", int(p))
+	}
+	return []byte(name), nil
+}
+
+func (p *Priority) UnmarshalText(text []byte) error {
+	for priority, name := range priorityNames {
+		if name == string(text) {
+			*p = priority
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown priority %q", text)
+}
+
+// next cycles none, low, medium, high and back to none.
+func (p Priority) next() Priority {
+	return (p + 1) % (PriorityHigh + 1)
+}
+
+// parsePriorityPrefix takes a leading !! (high), !- (low) or ! (medium) off
+// title.
+func parsePriorityPrefix(title string) (string, Priority) {
+	if rest, ok := strings.CutPrefix(title, "!!"); ok {
+		return strings.TrimSpace(rest), PriorityHigh
+	}
+	if rest, ok := strings.CutPrefix(title, "!-"); ok {
+		return strings.TrimSpace(rest), PriorityLow
+	}
+	if rest, ok := strings.CutPrefix(title, "!"); ok {
+		return strings.TrimSpace(rest), PriorityMedium
+	}
+	return title, PriorityNone
+}
+
+func (t *TodoList) SetPriority(index int, p Priority) error {
+	if !t.isValidIndex(index) {
+		return &ValidationError{Operation: "priority", Err: errors.New("invalid index")}
+	}
+	if _, ok := priorityNames[p]; !ok {
+		return &ValidationError{Operation: "priority", Err: fmt.Errorf("unknown priority %d", int(p))}
+	}
+	t.recordUndo()
+	t.items.update([]int{index}, func(item *TodoItem) { item.Priority = p })
+	t.save()
+	return nil
+}
+
+// CyclePriority moves the item at index to the next priority.
+func (t *TodoList) CyclePriority(index int) error {
+	if !t.isValidIndex(index) {
+		return &ValidationError{Operation: "priority", Err: errors.New("invalid index")}
+	}
+	return t.SetPriority(index, t.items.at(index).Priority.next())
+}
+
+// SortByPriority orders open items before completed ones and, within each,
+// higher priorities first. Ties keep their order and the cursor stays on the
+// selected item.
+func (t *TodoList) SortByPriority() {
+	t.sortItems(func(a, b TodoItem) int {
+		if a.Completed != b.Completed {
+			if a.Completed {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(b.Priority, a.Priority)
+	})
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+package main
+
+// raceEnabled reports a -race build, whose instrumentation makes timing
+// tests meaningless.
+const raceEnabled = true
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// frameFixture describes a model state for --render-frame. Every field is
+// optional. mode is one of "normal", "input", "palette", "locked",
+// "filter", "confirm", "links" or "paste"; filter is the locked title
+// filter, filterMode one of "all", "active", "completed" or "waiting", and
+// tagFilter a tag without its #. confirm is the question confirm mode asks,
+// links the URLs links mode offers (the selected item's by default), and
+// paste the text paste mode holds.
+//
+//	{
+//	  "items":  [{"title": "Exercise", "completed": true}],
+//	  "cursor": 0,
+//	  "mode":   "input",
+//	  "input":  {"action": "create", "content": "Read a book", "cursor": 4},
+//	  "confirm": {"prompt": "delete 2 items?", "details": ["a", "b"]},
+//	  "links":  ["https://example.com"],
+//	  "paste":  "first line\nsecond line",
+//	  "status": "item no longer exists",
+//	  "filter": "book",
+//	  "filterMode": "active",
+//	  "tagFilter": "home",
+//	  "showDetails": false,
+//	  "simple": false,
+//	  "modeLine": false,
+//	  "width":  80,
+//	  "height": 24
+//	}
+type frameFixture struct {
+	Items  []TodoItem `json:"items"`
+	Cursor int        `json:"cursor"`
+	Mode   string     `json:"mode"`
+	Input  struct {
+		Action  string `json:"action"`
+		Content string `json:"content"`
+		Cursor  int    `json:"cursor"`
+		Index   int    `json:"index"`
+	} `json:"input"`
+	Confirm struct {
+		Prompt  string   `json:"prompt"`
+		Details []string `json:"details"`
+	} `json:"confirm"`
+	Links       []string `json:"links"`
+	Paste       string   `json:"paste"`
+	Status      string   `json:"status"`
+	Filter      string   `json:"filter"`
+	FilterMode  string   `json:"filterMode"`
+	TagFilter   string   `json:"tagFilter"`
+	ShowDetails bool     `json:"showDetails"`
+	Simple      bool     `json:"simple"`
+	ModeLine    bool     `json:"modeLine"`
+	Width       int      `json:"width"`
+	Height      int      `json:"height"`
+}
+
+var fixtureModes = map[string]AppMode{
+	"":        ModeNormal,
+	"normal":  ModeNormal,
+	"input":   ModeInput,
+	"palette": ModePalette,
+	"locked":  ModeLocked,
+	"filter":  ModeSearch,
+	"confirm": ModeConfirm,
+	"links":   ModeLinks,
+	"paste":   ModePaste,
+}
+
+var fixtureActions = map[string]InputAction{
+	"":       ActionCreate,
+	"create": ActionCreate,
+	"edit":   ActionEdit,
+}
+
+// loadFrameFixture builds a model from the fixture at path.
+func loadFrameFixture(path string) (*TodoList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture frameFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, &ValidationError{Operation: "render frame", Err: fmt.Errorf("parse %s: %w", path, err)}
+	}
+
+	mode, ok := fixtureModes[fixture.Mode]
+	if !ok {
+		return nil, &ValidationError{Operation: "render frame", Err: fmt.Errorf("parse %s: unknown mode %q", path, fixture.Mode)}
+	}
+	action, ok := fixtureActions[fixture.Input.Action]
+	if !ok {
+		return nil, &ValidationError{Operation: "render frame", Err: fmt.Errorf("parse %s: unknown input action %q", path, fixture.Input.Action)}
+	}
+	filterMode, ok := FilterAll, fixture.FilterMode == ""
+	for mode, name := range filterModeNames {
+		if name == fixture.FilterMode {
+			filterMode, ok = mode, true
+		}
+	}
+	if !ok {
+		return nil, &ValidationError{Operation: "render frame", Err: fmt.Errorf("parse %s: unknown filter mode %q", path, fixture.FilterMode)}
+	}
+
+	t := NewTodoList(nil)
+	t.items = newItemList(fixture.Items)
+	t.selectedIndex = fixture.Cursor
+	t.currentMode = mode
+	t.statusMsg = fixture.Status
+	t.filter = fixture.Filter
+	t.filterMode = filterMode
+	t.tagFilter = fixture.TagFilter
+	t.keepSelectionVisible()
+	t.showDetails = fixture.ShowDetails
+	t.simpleMode = fixture.Simple
+	t.showModeLine = fixture.ModeLine
+	t.width = fixture.Width
+	t.height = fixture.Height
+	if mode != ModeNormal {
+		t.input = InputContext{
+			Action:  action,
+			Content: fixture.Input.Content,
+			Cursor:  min(max(fixture.Input.Cursor, 0), utf8.RuneCountInString(fixture.Input.Content)),
+			Index:   fixture.Input.Index,
+		}
+	}
+	switch mode {
+	case ModeConfirm:
+		t.confirm = confirmation{prompt: fixture.Confirm.Prompt, details: fixture.Confirm.Details}
+	case ModeLinks:
+		t.links = fixture.Links
+		if t.links == nil {
+			if item, ok := t.At(t.selectedIndex); ok {
+				t.links = item.urls()
+			}
+		}
+	case ModePaste:
+		t.paste = fixture.Paste
+	}
+	return t, nil
+}
+
+// renderFrame writes a single frame of the fixture at path to w. Output is
+// usually redirected to a file, where lipgloss would drop all styling, so
+// the frame is always rendered with 256 colours.
+func renderFrame(path string, w io.Writer) error {
+	t, err := loadFrameFixture(path)
+	if err != nil {
+		return err
+	}
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	_, err = fmt.Fprintln(w, t.View())
+	return err
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderFixture renders the fixture JSON through --render-frame's path.
+func renderFixture(t *testing.T, fixture string) string {
+	t.Helper()
+	profile := lipgloss.ColorProfile()
+	t.Cleanup(func() { lipgloss.SetColorProfile(profile) })
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(fixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := renderFrame(path, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestRenderFrameModes(t *testing.T) {
+	items := `"items": [{"title": "open https://a.example or https://b.example", "tags": ["home"]}, {"title": "done", "completed": true}]`
+	tests := []struct {
+		name    string
+		fixture string
+		want    []string
+	}{
+		{"confirm", `{` + items + `, "mode": "confirm", "confirm": {"prompt": "delete 2 items?", "details": ["open", "done"]}}`,
+			[]string{"delete 2 items?", "done"}},
+		{"links", `{` + items + `, "mode": "links"}`,
+			[]string{"https://a.example", "https://b.example"}},
+		{"paste", `{` + items + `, "mode": "paste", "paste": "one\ntwo"}`,
+			[]string{"pasted 2 lines", "paste as 2 separate items"}},
+		{"filter mode", `{` + items + `, "filterMode": "completed"}`,
+			[]string{"1/2 items (completed only)"}},
+		{"tag filter", `{` + items + `, "tagFilter": "home"}`,
+			[]string{"1/2 items (#home)"}},
+		{"details", `{` + items + `, "showDetails": true}`,
+			[]string{"added at an unknown time"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := renderFixture(t, tt.fixture)
+			for _, want := range tt.want {
+				if !strings.Contains(frame, want) {
+					t.Errorf("frame does not show %q:\n%s", want, frame)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderFrameKeepsStyling(t *testing.T) {
+	frame := renderFixture(t, `{"items": [{"title": "done", "completed": true}, {"title": "open"}], "modeLine": true}`)
+	if !strings.Contains(frame, "\x1b[") {
+		t.Errorf("frame has no styling:\n%s", frame)
+	}
+}
+
+func TestRenderFrameRejectsUnknownFilterMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(`{"filterMode": "someday"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadFrameFixture(path); err == nil {
+		t.Error("loaded a fixture with an unknown filter mode")
+	}
+}
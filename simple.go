@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Simple mode is meant for small or remote terminals: rows are numbered and
+// spaced out, a number key selects an item, and the footer always shows a
+// reduced set of keys. Once a number picks an item the footer turns into a
+// menu of what can be done to it, so every action takes two keys: the
+// number, then the action. Every action goes through the normal-mode
+// handlers. Simple mode renders inline, without the alternate screen or
+// mouse reporting, which flicker and misbehave over flaky connections.
+
+var simpleModeHints = []footerHint{
+	{"1-9: pick item", 1},
+	{"space: toggle", 2},
+	{"n: new", 3},
+	{"e: edit", 4},
+	{"d: delete", 5},
+	{"m: full mode", 6},
+	{"q: quit", 0},
+}
+
+// simpleActionHints is the menu shown once a number has picked an item.
+var simpleActionHints = []footerHint{
+	{"space: toggle", 1},
+	{"e: edit", 2},
+	{"d: delete", 3},
+	{"p: priority", 4},
+	{"esc: cancel", 0},
+}
+
+// simpleModeFooter returns the hints simple mode shows: the action menu for
+// the picked item, or the reduced keymap.
+func (t TodoList) simpleModeFooter() []footerHint {
+	pos := visiblePos(t.visibleItems(), t.selectedIndex)
+	if !t.actionMenu || pos < 0 {
+		return simpleModeHints
+	}
+	return append([]footerHint{{"item " + strconv.Itoa(pos+1) + ":", 0}}, simpleActionHints...)
+}
+
+// selectByNumber moves the cursor to the 1-based row number typed so far,
+// counting only the rows the filter shows. Digits accumulate while a longer
+// number could still name an item, so "1" then "2" reaches item 12 on a
+// long list.
+func (t *TodoList) selectByNumber(digit int) bool {
+	visible := t.visibleItems()
+	number := t.pendingNumber*10 + digit
+	if number < 1 || number > len(visible) {
+		t.pendingNumber = 0
+		return false
+	}
+
+	t.selectedIndex = visible[number-1]
+	t.pendingNumber = 0
+	if number*10 <= len(visible) {
+		t.pendingNumber = number
+	}
+	return true
+}
+
+// toggleSimpleMode switches between simple and full mode, leaving or
+// re-entering the alternate screen and mouse reporting to match.
+func (t *TodoList) toggleSimpleMode() tea.Cmd {
+	t.simpleMode = !t.simpleMode
+	t.pendingNumber = 0
+	t.actionMenu = false
+	if t.simpleMode {
+		return tea.Batch(tea.ExitAltScreen, tea.DisableMouse)
+	}
+	if t.mouse {
+		return tea.Batch(tea.EnterAltScreen, tea.EnableMouseCellMotion)
+	}
+	return tea.EnterAltScreen
+}
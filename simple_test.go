@@ -0,0 +1,81 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// cmdMsgs runs cmd and returns the messages it produces, looking inside
+// batches.
+func cmdMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, cmd := range batch {
+		msgs = append(msgs, cmdMsgs(cmd)...)
+	}
+	return msgs
+}
+
+func TestSimpleModeActionMenu(t *testing.T) {
+	list := newTestList(t, "one", "two", "three")
+	list.simpleMode = true
+
+	list = press(t, list, "2")
+	footer := ansi.Strip(list.View())
+	if !strings.Contains(footer, "item 2:") || !strings.Contains(footer, "space: toggle") {
+		t.Fatalf("after picking item 2 the view shows no action menu:\n%s", footer)
+	}
+
+	list = press(t, list, " ")
+	if !list.items.at(1).Completed {
+		t.Errorf("the menu's space did not toggle item 2")
+	}
+	if strings.Contains(ansi.Strip(list.View()), "item 2:") {
+		t.Errorf("the menu stayed open after its action ran")
+	}
+
+	// esc closes the menu rather than quitting.
+	list = press(t, list, "3")
+	list, cmd := send(t, list, keyMsg("esc"))
+	if isQuit(cmd) {
+		t.Fatal("esc in the action menu quit")
+	}
+	if list.actionMenu {
+		t.Error("esc left the action menu open")
+	}
+}
+
+func TestToggleSimpleModeSwitchesScreen(t *testing.T) {
+	list := newTestList(t, "one")
+	list.mouse = true
+
+	list, cmd := send(t, list, keyMsg("m"))
+	msgs := cmdMsgs(cmd)
+	if !list.simpleMode || !slices.Contains(msgs, tea.ExitAltScreen()) || !slices.Contains(msgs, tea.DisableMouse()) {
+		t.Fatalf("entering simple mode sent %v, want the alternate screen and mouse off", msgs)
+	}
+
+	list, cmd = send(t, list, keyMsg("m"))
+	msgs = cmdMsgs(cmd)
+	if list.simpleMode || !slices.Contains(msgs, tea.EnterAltScreen()) || !slices.Contains(msgs, tea.EnableMouseCellMotion()) {
+		t.Fatalf("leaving simple mode sent %v, want the alternate screen and mouse on", msgs)
+	}
+
+	list.mouse = false
+	list = press(t, list, "m")
+	_, cmd = send(t, list, keyMsg("m"))
+	if msgs := cmdMsgs(cmd); slices.Contains(msgs, tea.EnableMouseCellMotion()) {
+		t.Errorf("mouse reporting came back on with -mouse=false: %v", msgs)
+	}
+}
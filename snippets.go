@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// snippets maps a \name token typed in input mode to its expansion. Tab after
+// the token replaces it with the expanded text.
+var snippets = map[string]func(now time.Time) string{
+	"date": func(now time.Time) string { return now.Format("2006-01-02") },
+	"time": func(now time.Time) string { return now.Format("15:04") },
+}
+
+// expandSnippet replaces a \name token ending at the cursor with its snippet.
+// The token must start a word, so text produced by an earlier expansion is
+// never expanded again. It reports whether anything was expanded.
+func (t *TodoList) expandSnippet() bool {
+	before, after := t.input.split()
+	start := strings.LastIndexByte(before, '\\')
+	if start < 0 || (start > 0 && before[start-1] != ' ') {
+		return false
+	}
+
+	expand, ok := snippets[before[start+1:]]
+	if !ok {
+		return false
+	}
+
+	before = before[:start] + expand(time.Now())
+	t.input.Content = before + after
+	t.input.Cursor = utf8.RuneCountInString(before)
+	return true
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// storeVersion is written to every data file so the format can evolve.
+const storeVersion = 1
+
+// Store reads and writes the list as a JSON file. Saves may run
+// concurrently from background commands, so the store serializes them.
+type Store struct {
+	path string
+
+	mu      sync.Mutex
+	written int
+}
+
+type storeFile struct {
+	Version int        `json:"version"`
+	Items   []TodoItem `json:"items"`
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultStorePath is $LAZYLIST_FILE when set, otherwise
+// $XDG_DATA_HOME/lazylist/todos.json, falling back to ~/.local/share when
+// XDG_DATA_HOME is not set.
+func DefaultStorePath() (string, error) {
+	if path := os.Getenv("LAZYLIST_FILE"); path != "" {
+		return path, nil
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("locate data directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "lazylist", "todos.json"), nil
+}
+
+// resolveStorePath picks the data file from --file or a single positional
+// argument, falling back to DefaultStorePath.
+func resolveStorePath(flagPath string, args []string) (string, error) {
+	if len(args) > 1 {
+		return "", &UsageError{Err: fmt.Errorf("expected at most one file, got %d", len(args))}
+	}
+	if len(args) == 1 {
+		if flagPath != "" && flagPath != args[0] {
+			return "", &UsageError{Err: fmt.Errorf("both --file %q and %q given", flagPath, args[0])}
+		}
+		return args[0], nil
+	}
+	if flagPath != "" {
+		return flagPath, nil
+	}
+	return DefaultStorePath()
+}
+
+// ensureStoreFile creates an empty list at path if nothing is there yet, and
+// rejects paths that can never hold a list.
+func ensureStoreFile(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return SaveTodos(path, nil)
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory, not a list file", path)
+	}
+	return nil
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+func (s *Store) Load() ([]TodoItem, error) {
+	return LoadTodos(s.path)
+}
+
+// SaveIfNewer saves a snapshot numbered seq unless a later snapshot has
+// already been written, so background saves finishing out of order never
+// put an older list back on disk.
+func (s *Store) SaveIfNewer(seq int, items []TodoItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if seq <= s.written {
+		return nil
+	}
+	if err := SaveTodos(s.path, items); err != nil {
+		return err
+	}
+	s.written = seq
+	return nil
+}
+
+// LoadTodos reads the items in the data file at path. A missing file returns
+// an error matching fs.ErrNotExist; a file that cannot be parsed returns a
+// *ValidationError.
+func LoadTodos(path string) ([]TodoItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseStore(data, path)
+}
+
+// parseStore decodes the contents of a data file; name is used in errors.
+func parseStore(data []byte, name string) ([]TodoItem, error) {
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, &ValidationError{Operation: "load", Err: fmt.Errorf("parse %s: %w", name, err)}
+	}
+	if file.Version > storeVersion {
+		return nil, &ValidationError{Operation: "load", Err: fmt.Errorf("%s was written by a newer lazylist (version %d)", name, file.Version)}
+	}
+	return file.Items, nil
+}
+
+// SaveTodos writes the items to a temporary file and renames it into place,
+// so a failed write never leaves a truncated list behind. Missing parent
+// directories are created.
+func SaveTodos(path string, items []TodoItem) error {
+	if items == nil {
+		items = []TodoItem{}
+	}
+	data, err := json.MarshalIndent(storeFile{Version: storeVersion, Items: items}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("save %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("save %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("save %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("save %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("save %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("save %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadList opens the list in store for the TUI. Problems that still allow a
+// usable session come back as a status message: a missing file starts an
+// empty list, and an unreadable one is moved aside so the next save cannot
+// overwrite it.
+func loadList(store *Store) ([]TodoItem, string, error) {
+	items, err := store.Load()
+	if err == nil {
+		return items, "", nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Sprintf("starting a new list at %s", store.Path()), nil
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, "", err
+	}
+	backup := store.Path() + ".corrupt"
+	if renameErr := os.Rename(store.Path(), backup); renameErr != nil {
+		return nil, "", fmt.Errorf("%w (and could not move it aside: %v)", err, renameErr)
+	}
+	return nil, fmt.Sprintf("warning: %v; moved it to %s and started an empty list", validationErr.Err, backup), nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Tags are #word tokens taken out of a title and shown dimmed after it.
+// The tag filter shows only items with one tag; like the text filter it
+// hides items without moving the cursor off the full items slice.
+
+// tagToken matches a #tag. It must start with a letter so issue numbers
+// like #12 stay in the title.
+var tagToken = regexp.MustCompile(`^#(\p{L}[\p{L}\p{N}_-]*)$`)
+
+// parseTags takes #tag tokens out of text and returns them lowercased,
+// without duplicates, in order of appearance.
+func parseTags(text string) (string, []string) {
+	var tags []string
+	title, _ := takeTokens(text, func(word string) (bool, error) {
+		match := tagToken.FindStringSubmatch(word)
+		if match == nil {
+			return false, nil
+		}
+		if tag := strings.ToLower(match[1]); !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+		return true, nil
+	})
+	return title, tags
+}
+
+// formatTags renders tags as they are typed, for display and editing.
+func formatTags(tags []string) string {
+	words := make([]string, len(tags))
+	for i, tag := range tags {
+		words[i] = "#" + tag
+	}
+	return strings.Join(words, " ")
+}
+
+// knownTags returns every tag in use, sorted.
+func (t TodoList) knownTags() []string {
+	var tags []string
+	for _, item := range t.items.all() {
+		tags = append(tags, item.Tags...)
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// cycleTagFilter steps the tag filter through the known tags and back to
+// showing every tag. It reports false when no item has a tag.
+func (t *TodoList) cycleTagFilter() bool {
+	tags := t.knownTags()
+	if len(tags) == 0 && t.tagFilter == "" {
+		return false
+	}
+	next := slices.IndexFunc(tags, func(tag string) bool { return tag > t.tagFilter })
+	if next < 0 {
+		t.tagFilter = ""
+		return true
+	}
+	t.tagFilter = tags[next]
+	return true
+}
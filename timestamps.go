@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Items record when they were added and completed. Items saved before the
+// timestamps existed have zero times, which read as unknown rather than as
+// dates in 1970.
+
+// setCompleted marks the item done or open, stamping when it was done. An
+// item that is already in that state keeps its timestamp.
+func (item *TodoItem) setCompleted(done bool, now time.Time) {
+	if item.Completed == done {
+		return
+	}
+	item.Completed = done
+	item.CompletedAt = time.Time{}
+	if done {
+		item.CompletedAt = now
+	}
+}
+
+// relativeTime describes then as seen from now: minutes and hours within
+// the same day, days and weeks within a month, and the date after that.
+func relativeTime(then, now time.Time) string {
+	if then.IsZero() {
+		return "at an unknown time"
+	}
+	elapsed := now.Sub(then)
+	days := dateOf(then.In(now.Location())).daysUntil(dateOf(now))
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return countAgo(int(elapsed.Minutes()), "minute")
+	case days == 0:
+		return countAgo(int(elapsed.Hours()), "hour")
+	case days == 1:
+		return "yesterday"
+	case days < 7:
+		return countAgo(days, "day")
+	case days < 30:
+		return countAgo(days/7, "week")
+	case then.Year() == now.Year():
+		return "on " + then.Format("Jan 2")
+	}
+	return "on " + then.Format("Jan 2 2006")
+}
+
+func countAgo(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit + " ago"
+	}
+	return fmt.Sprintf("%d %ss ago", n, unit)
+}
+
+// detailLine describes the item's age, shown under the selected row after
+// i is pressed.
+func (item TodoItem) detailLine(now time.Time) string {
+	line := "added " + relativeTime(item.CreatedAt, now)
+	if item.Completed {
+		line += ", completed " + relativeTime(item.CompletedAt, now)
+	}
+	return line
+}
+
+// detailRows is the number of lines the detail line adds to the list.
+func (t TodoList) detailRows() int {
+	if t.showDetails && t.currentMode != ModeLocked && t.hasSelection() {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelativeTimeAcrossDaylightSaving(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	// Clocks go forward on Mar 10 2030, so that day is 23 hours long.
+	now := time.Date(2030, 3, 11, 0, 30, 0, 0, newYork)
+	tests := []struct {
+		then time.Time
+		want string
+	}{
+		{time.Date(2030, 3, 10, 0, 30, 0, 0, newYork), "yesterday"},
+		{time.Date(2030, 3, 9, 23, 0, 0, 0, newYork), "2 days ago"},
+		{time.Date(2030, 3, 4, 12, 0, 0, 0, newYork), "1 week ago"},
+		{time.Date(2030, 3, 5, 12, 0, 0, 0, newYork), "6 days ago"},
+		// A timestamp saved in another zone is read on the local calendar.
+		{time.Date(2030, 3, 10, 6, 0, 0, 0, time.UTC), "yesterday"},
+	}
+	for _, tt := range tests {
+		if got := relativeTime(tt.then, now); got != tt.want {
+			t.Errorf("relativeTime(%v) = %q, want %q", tt.then, got, tt.want)
+		}
+	}
+
+	// The 25-hour day clocks go back.
+	now = time.Date(2030, 11, 4, 0, 30, 0, 0, newYork)
+	if got := relativeTime(time.Date(2030, 11, 3, 0, 0, 0, 0, newYork), now); got != "yesterday" {
+		t.Errorf("across the autumn change: %q, want yesterday", got)
+	}
+}
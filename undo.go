@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxUndo caps the undo history to bound memory.
+const maxUndo = 100
+
+// snapshot is the list state before a mutation.
+type snapshot struct {
+	items         itemList
+	selectedIndex int
+}
+
+func (t *TodoList) snapshot() snapshot {
+	return snapshot{items: t.items, selectedIndex: t.selectedIndex}
+}
+
+func (t *TodoList) restore(s snapshot) {
+	t.items = s.items
+	t.selectedIndex = s.selectedIndex
+	t.adjustCursorAfterDelete()
+	// The restored list may not contain the deleted item the notice refers to.
+	t.recentlyDeleted = nil
+	t.save()
+}
+
+// recordUndo saves the current state before a mutation. A new mutation
+// invalidates anything that could have been redone, and ends the delete
+// notice since u would no longer undo that delete.
+func (t *TodoList) recordUndo() {
+	t.pushUndo(t.snapshot())
+}
+
+// pushUndo records s as the state to return to on undo.
+func (t *TodoList) pushUndo(s snapshot) {
+	t.recentlyDeleted = nil
+	t.undoStack = append(t.undoStack, s)
+	if len(t.undoStack) > maxUndo {
+		t.undoStack = t.undoStack[len(t.undoStack)-maxUndo:]
+	}
+	t.redoStack = nil
+}
+
+func (t *TodoList) Undo() error {
+	if len(t.undoStack) == 0 {
+		return &ValidationError{Operation: "undo", Err: errors.New("nothing to undo")}
+	}
+	last := t.undoStack[len(t.undoStack)-1]
+	t.undoStack = t.undoStack[:len(t.undoStack)-1]
+	t.redoStack = append(t.redoStack, t.snapshot())
+	restored := addedIndex(t.items, last.items)
+	t.restore(last)
+	if restored >= 0 {
+		t.selectedIndex = restored
+		t.statusMsg = fmt.Sprintf("restored '%s'", t.items.at(restored).Title)
+	} else {
+		t.statusMsg = "undid last change"
+	}
+	return nil
+}
+
+func (t *TodoList) Redo() error {
+	if len(t.redoStack) == 0 {
+		return &ValidationError{Operation: "redo", Err: errors.New("nothing to redo")}
+	}
+	next := t.redoStack[len(t.redoStack)-1]
+	t.redoStack = t.redoStack[:len(t.redoStack)-1]
+	t.undoStack = append(t.undoStack, t.snapshot())
+	t.restore(next)
+	t.statusMsg = "redid last change"
+	return nil
+}
+
+// addedIndex returns the index of the single item after has over before, or
+// -1 when after is not before with one item put back. Chunks the two lists
+// share are skipped whole.
+func addedIndex(before, after itemList) int {
+	if after.Len() != before.Len()+1 {
+		return -1
+	}
+	for i := 0; i < before.Len(); {
+		cb, ib := before.locate(i)
+		ca, ia := after.locate(i)
+		if ib == 0 && ia == 0 && sameChunk(before.chunks[cb], after.chunks[ca]) {
+			i += len(before.chunks[cb])
+			continue
+		}
+		if before.at(i).Title != after.at(i).Title {
+			return i
+		}
+		i++
+	}
+	return before.Len()
+}
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestDeleteThenUndoRestoresItemAndCursor(t *testing.T) {
+	list := newTestList(t, "a", "b", "c")
+	list.confirmDelete = false
+	list = press(t, list, "j", "d")
+	assertTitles(t, list, "a", "c")
+	if list.recentlyDeleted == nil {
+		t.Fatal("no restore notice after delete")
+	}
+
+	list = press(t, list, "u")
+	assertTitles(t, list, "a", "b", "c")
+	if list.selectedIndex != 1 {
+		t.Errorf("cursor on %d after undo, want 1", list.selectedIndex)
+	}
+	if want := "restored 'b'"; list.statusMsg != want {
+		t.Errorf("status = %q, want %q", list.statusMsg, want)
+	}
+	if list.recentlyDeleted != nil {
+		t.Error("restore notice still showing after the item came back")
+	}
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// View components. Each renders one region of the screen as lines no wider
+// than width. The layout in layout.go decides how they stack, and the list
+// gets the rows the others leave.
+
+func (t TodoList) headerLines(width int) []string {
+	header := fmt.Sprintf("you have %d items on your list:", t.items.Len())
+	if t.filtered() {
+		var filters []string
+		if t.filterMode != FilterAll {
+			filters = append(filters, t.filterMode.String()+" only")
+		}
+		if t.tagFilter != "" {
+			filters = append(filters, "#"+t.tagFilter)
+		}
+		if query := t.filterQuery(); query != "" {
+			if t.currentMode == ModeLocked {
+				query = maskedTitle
+			}
+			filters = append(filters, "filter: "+query)
+		}
+		header = fmt.Sprintf("%d/%d items (%s)", len(t.visibleItems()), t.items.Len(), strings.Join(filters, ", "))
+	}
+	header = truncateToWidth(header, width)
+	if t.visualBellOn {
+		header = invertedStyle.Render(header)
+	}
+	return []string{header, ""}
+}
+
+// listLines renders the visible items followed by a blank separator. When
+// height is too small for every item, it shows the window from visibleRange
+// between lines counting the items hidden above and below. Simple mode
+// spaces the rows out with a blank line after each item.
+func (t TodoList) listLines(width, height int) []string {
+	visible := t.visibleItems()
+	if len(visible) == 0 && t.filtered() {
+		return []string{dimStyle.Render("  nothing matches the filter"), ""}
+	}
+	start, end := t.visibleRange(height)
+	scrolled := end-start < len(visible)
+
+	lines := make([]string, 0, (end-start)*t.rowHeight()+scrollIndicators+1)
+	if scrolled {
+		lines = append(lines, scrollIndicator("↑", start))
+	}
+	for pos := start; pos < end; pos++ {
+		lines = append(lines, t.itemLine(visible[pos], pos+1, len(visible), width))
+		if visible[pos] == t.selectedIndex && t.detailRows() > 0 {
+			detail := t.items.at(visible[pos]).detailLine(time.Now())
+			lines = append(lines, dimStyle.Render(truncateToWidth("      "+detail, width)))
+		}
+		if t.simpleMode && pos < end-1 {
+			lines = append(lines, "")
+		}
+	}
+	if scrolled {
+		lines = append(lines, scrollIndicator("↓", len(visible)-end))
+	}
+	return append(lines, "")
+}
+
+// itemLine renders items[index]. number is its 1-based position among the
+// total visible rows, shown in simple mode, and width decides which optional
+// parts are shown.
+func (t TodoList) itemLine(index, number, total, width int) string {
+	item := t.items.at(index)
+	bp := breakpointFor(width)
+
+	cursor := "  "
+	if t.selectedIndex == index {
+		cursor = "> "
+	}
+
+	checked := "[ ] "
+	switch {
+	case item.Completed:
+		checked = "[x] "
+	case item.Waiting:
+		checked = waitingStyle.Render("[~]") + " "
+	}
+
+	if t.simpleMode {
+		// Pad the numbers so titles line up past item 9.
+		digits := len(strconv.Itoa(total))
+		cursor += fmt.Sprintf("%*d. ", digits, number)
+	}
+
+	if t.currentMode == ModeLocked {
+		return cursor + checked + maskedTitle
+	}
+	prefix := cursor + checked
+	if marker, ok := priorityMarkers[item.Priority]; ok && bp.markers {
+		prefix += marker + " "
+	}
+	var suffix string
+	if item.DueDate != nil && bp.dueDates {
+		suffix += " " + item.dueLabel(time.Now())
+	}
+	if len(item.Tags) > 0 && bp.extras {
+		suffix += dimStyle.Render(" " + formatTags(item.Tags))
+	}
+	if len(item.Fields) > 0 && bp.extras {
+		suffix += dimStyle.Render(" " + formatFields(item.Fields))
+	}
+	if bp.extras && len(t.itemWarnings(index)) > 0 {
+		suffix += dimStyle.Render(" " + warningMarker)
+	}
+
+	// The title gives way to the width first; the details after it are
+	// dropped when they would leave it less than minTitleWidth cells.
+	title := item.Title
+	if width > 0 {
+		room := width - cellWidth(prefix) - cellWidth(suffix)
+		if room < minTitleWidth && suffix != "" {
+			suffix = ""
+			room = width - cellWidth(prefix)
+		}
+		title = truncateToWidth(title, max(room, 1))
+	}
+	if item.Waiting {
+		title = waitingStyle.Render(title)
+	}
+	return prefix + title + suffix
+}
+
+// minTitleWidth is the fewest cells a title is squeezed to before the
+// details after it are dropped.
+const minTitleWidth = 12
+
+// panelLines renders the interactive area below the list for the current
+// mode.
+func (t TodoList) panelLines(width int) []string {
+	switch t.currentMode {
+	case ModeInput:
+		return t.inputLines(width)
+	case ModePalette:
+		return t.paletteLines(width)
+	case ModeLocked:
+		return t.lockLines(width)
+	case ModeConfirm:
+		return t.confirmLines(width)
+	case ModeLinks:
+		return t.linkLines(width)
+	case ModePaste:
+		return t.pasteLines(width)
+	case ModeSearch:
+		return []string{
+			truncateToWidth("filter (enter to keep, esc to clear):", width),
+			t.inputLine(width),
+		}
+	}
+	return nil
+}
+
+func (t TodoList) inputLines(width int) []string {
+	actionText := "edit item"
+	if t.input.Action == ActionCreate {
+		actionText = "enter new item"
+	}
+
+	lines := []string{
+		truncateToWidth(fmt.Sprintf("%s (esc to cancel, tab to expand \\date or \\time):", actionText), width),
+		t.inputLine(width),
+	}
+
+	if t.draftOffered {
+		lines = append(lines, dimStyle.Render(truncateToWidth("  resume previous draft? (up-arrow)", width)))
+	}
+
+	normalized := strings.TrimSpace(t.titleRules.Apply(t.input.Content))
+	if normalized != strings.TrimSpace(t.input.Content) {
+		lines = append(lines, dimStyle.Render(truncateToWidth("  saves as: "+normalized, width)))
+	}
+	skip := -1
+	if t.input.Action == ActionEdit {
+		skip = t.input.Index
+	}
+	// Warn about the title the item would be saved with, after the
+	// due date, tags and other tokens are taken out of it.
+	title := normalized
+	if item, _, err := parseItemInput(normalized, time.Now()); err == nil {
+		title = item.Title
+	}
+	for _, warning := range t.titleWarnings(title, skip) {
+		lines = append(lines, dimStyle.Render(truncateToWidth("  "+warningMarker+" "+warning.Err.Error(), width)))
+	}
+	return lines
+}
+
+// inputLine renders the input buffer with a | at the cursor. When it is wider
+// than width, text is cut from both ends so the cursor stays in view.
+func (t TodoList) inputLine(width int) string {
+	before, after := t.input.split()
+	line := "> " + before + "|" + after
+	if width <= 0 || displayWidth.StringWidth(line) <= width {
+		return line
+	}
+
+	room := max(width-displayWidth.StringWidth("> |"), 2)
+	beforeRoom := max(room-displayWidth.StringWidth(after), room/2)
+	if w := displayWidth.StringWidth(before); w > beforeRoom {
+		before = displayWidth.TruncateLeft(before, w-beforeRoom+1, ellipsis)
+	}
+	after = displayWidth.Truncate(after, room-displayWidth.StringWidth(before), ellipsis)
+	return "> " + before + "|" + after
+}
+
+func (t TodoList) statusLines(width int) []string {
+	var lines []string
+	if t.lastErr != nil && t.currentMode != ModeLocked {
+		lines = append(lines, errorStyle.Render(truncateToWidth("error: "+t.lastErr.Error(), width)))
+	}
+	if t.currentMode != ModeNormal {
+		return lines
+	}
+
+	if t.filter != "" {
+		lines = append(lines, dimStyle.Render(truncateToWidth("/"+t.filter+" (/ to change, esc to clear)", width)))
+	}
+	if t.count > 0 {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("count: %d", t.count)))
+	}
+	if t.statusMsg != "" {
+		lines = append(lines, truncateToWidth(t.statusMsg, width))
+	}
+	bp := breakpointFor(width)
+	if t.isValidIndex(t.selectedIndex) {
+		item := t.items.at(t.selectedIndex)
+		if item.DueDate != nil && !bp.dueDates {
+			lines = append(lines, item.dueLabel(time.Now()))
+		}
+		for _, warning := range t.itemWarnings(t.selectedIndex) {
+			lines = append(lines, dimStyle.Render(truncateToWidth(warningMarker+" "+warning.Err.Error(), width)))
+		}
+	}
+	if t.recentlyDeleted != nil {
+		lines = append(lines, truncateToWidth(fmt.Sprintf("deleted '%s' — press u to restore", t.recentlyDeleted.Item.Title), width))
+	}
+	if bp.compact && len(lines) > 1 {
+		lines = lines[:1]
+	}
+	return lines
+}
+
+// footerLines renders the key hints in normal mode, preceded by the mode
+// line when it is enabled. Other modes show only the mode line.
+func (t TodoList) footerLines(width int) []string {
+	var segment string
+	if t.showModeLine {
+		segment = t.modeSegment()
+	}
+
+	if t.currentMode != ModeNormal {
+		if segment == "" {
+			return nil
+		}
+		return []string{segment}
+	}
+
+	hints := normalModeHints
+	if t.simpleMode {
+		hints = t.simpleModeFooter()
+	}
+	if breakpointFor(width).compact {
+		if segment != "" {
+			return []string{segment}
+		}
+		hints = []footerHint{slices.MinFunc(hints, func(a, b footerHint) int { return a.drop - b.drop })}
+	} else if t.store != nil {
+		hints = append([]footerHint{{"list: " + filepath.Base(t.store.Path()), 0}}, hints...)
+	}
+	hintsWidth := width
+	if segment != "" && width > 0 {
+		hintsWidth = max(width-cellWidth(segment)-1, 1)
+	}
+	footer := fitHints(hints, hintsWidth)
+	if segment != "" {
+		footer = segment + " " + footer
+	}
+	return []string{footer}
+}
+
+// modeSegment renders the current mode like vim's "-- INSERT --", including
+// the sub-mode where one applies.
+func (t TodoList) modeSegment() string {
+	info, ok := modes[t.currentMode]
+	if !ok {
+		return ""
+	}
+
+	name := info.name
+	if t.currentMode == ModeInput && t.input.Action == ActionEdit {
+		name += " (edit)"
+	}
+	return lipgloss.NewStyle().Bold(true).Foreground(info.color).Render("-- " + name + " --")
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestInputWarningsUseParsedTitle(t *testing.T) {
+	tests := []struct {
+		typed string
+		warn  string
+	}{
+		{"pay rent due:friday", ""},
+		{"pay rent @tomorrow", ""},
+		{"pay rent due:someday", "unresolved due: token"},
+		{"!! water plants due:friday #home", "duplicate of a completed item"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typed, func(t *testing.T) {
+			list := newTestList(t, "water plants")
+			list = press(t, list, " ", "n")
+			list = typeText(t, list, tt.typed)
+
+			panel := strings.Join(list.inputLines(80), "\n")
+			hasWarning := strings.Contains(panel, warningMarker)
+			if tt.warn == "" && hasWarning {
+				t.Errorf("typing %q warns:\n%s", tt.typed, panel)
+			}
+			if tt.warn != "" && !strings.Contains(panel, tt.warn) {
+				t.Errorf("typing %q does not warn %q:\n%s", tt.typed, tt.warn, panel)
+			}
+		})
+	}
+}
+
+// TestFramesFitNarrowTerminals checks that no line of a frame is wider
+// than the terminal, so none wraps, and the frame is no taller than it.
+func TestFramesFitNarrowTerminals(t *testing.T) {
+	long := strings.Repeat("very long words ", 12)
+	scenarios := []struct {
+		name  string
+		setup func(TodoList) TodoList
+	}{
+		{"normal", func(l TodoList) TodoList { return l }},
+		{"saves as", func(l TodoList) TodoList {
+			l.titleRules = TitleRules{CollapseSpaces: true, Capitalize: true}
+			return typeText(t, press(t, l, "n"), "  "+long+"  ")
+		}},
+		{"passphrase", func(l TodoList) TodoList {
+			l.lockPhrase = "secret"
+			l.lock()
+			return typeText(t, l, long)
+		}},
+		{"unlock prompt", func(l TodoList) TodoList {
+			l.lock()
+			return l
+		}},
+	}
+	for _, width := range []int{40, 60, 80} {
+		for _, sc := range scenarios {
+			t.Run(fmt.Sprintf("%s/%d", sc.name, width), func(t *testing.T) {
+				list := newTestList(t, "short", long)
+				list, _ = send(t, list, tea.WindowSizeMsg{Width: width, Height: 20})
+				list = sc.setup(list)
+
+				lines := strings.Split(list.View(), "\n")
+				if len(lines) > 20 {
+					t.Errorf("frame is %d lines, terminal is 20", len(lines))
+				}
+				for i, line := range lines {
+					if w := lipgloss.Width(line); w > width {
+						t.Errorf("line %d is %d cells wide, terminal is %d: %q", i, w, width, line)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestItemLineMixedWidthGolden pins item rows holding wide, combining and
+// ambiguous-width characters at 40 columns, with ambiguous characters
+// narrow and wide. The prefix, its ↓ marker included, must be measured by
+// the same rules the title is cut by, or the row overflows by a cell.
+func TestItemLineMixedWidthGolden(t *testing.T) {
+	tests := []struct {
+		title         string
+		ambiguousWide bool
+		want          string
+	}{
+		{"買い物リストを作る、牛乳と卵", false, "> [ ] ↓ 買い物リストを作る、牛乳と卵"},
+		{"🎉 party planning for saturday night", false, "> [ ] ↓ 🎉 party planning for saturday …"},
+		{"café naïve résumé étude long title here", false, "> [ ] ↓ café naïve résumé étude long ti…"},
+		{"→ ambiguous ±½ signs α β γ δ ε ζ", false, "> [ ] ↓ → ambiguous ±½ signs α β γ δ ε ζ"},
+		{"買い物リストを作る、牛乳と卵", true, "> [ ] ↓ 買い物リストを作る、牛乳と卵"},
+		{"🎉 party planning for saturday night", true, "> [ ] ↓ 🎉 party planning for saturda…"},
+		{"café naïve résumé étude long title here", true, "> [ ] ↓ café naïve résumé étude l…"},
+		{"→ ambiguous ±½ signs α β γ δ ε ζ", true, "> [ ] ↓ → ambiguous ±½ signs α β…"},
+	}
+	defer func(wide bool) { displayWidth.EastAsianWidth = wide }(displayWidth.EastAsianWidth)
+	for _, tt := range tests {
+		displayWidth.EastAsianWidth = tt.ambiguousWide
+		list := newTestList(t, tt.title)
+		list.items.set(0, TodoItem{Title: tt.title, Priority: PriorityLow})
+
+		got := ansi.Strip(list.itemLine(0, 1, 1, 40))
+		if got != tt.want {
+			t.Errorf("ambiguous wide %v:\n got %q\nwant %q", tt.ambiguousWide, got, tt.want)
+		}
+		if w := displayWidth.StringWidth(got); w > 40 {
+			t.Errorf("ambiguous wide %v: %q is %d cells", tt.ambiguousWide, got, w)
+		}
+	}
+}
+
+func TestWindowSizeIsStored(t *testing.T) {
+	list := newTestList(t, "a", strings.Repeat("long title ", 10))
+	list, _ = send(t, list, tea.WindowSizeMsg{Width: 50, Height: 12})
+	if list.width != 50 || list.height != 12 {
+		t.Fatalf("size %dx%d, want 50x12", list.width, list.height)
+	}
+	for i, line := range strings.Split(list.View(), "\n") {
+		if w := lipgloss.Width(line); w > 50 {
+			t.Errorf("line %d is %d cells wide after resizing to 50: %q", i, w, line)
+		}
+	}
+}
+
+func TestWarningsFollowTheList(t *testing.T) {
+	// As loaded from disk: nothing was typed in this session.
+	list := newTestList(t, "water plants", "water plants")
+	list.items.update([]int{0}, func(item *TodoItem) { item.Completed = true })
+	// warned reports whether the open item, the last one, is marked.
+	warned := func(list TodoList) bool {
+		last := list.Len() - 1
+		return strings.Contains(list.itemLine(last, last+1, list.Len(), 80), warningMarker)
+	}
+	if !warned(list) {
+		t.Fatal("loaded duplicate of a completed item has no warning marker")
+	}
+	if status := strings.Join(press(t, list, "j").statusLines(80), "\n"); !strings.Contains(status, "duplicate of a completed item") {
+		t.Errorf("selected duplicate does not list its warning:\n%s", status)
+	}
+
+	if warned(press(t, list, " ")) {
+		t.Error("warning stayed after the completed item was reopened")
+	}
+	list.confirmDelete = false
+	if warned(press(t, list, "d")) {
+		t.Error("warning stayed after the completed item was deleted")
+	}
+}
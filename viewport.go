@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+)
+
+// The list scrolls when it has more rows than the screen. scrollOffset is
+// the first visible row shown and only moves when the cursor would leave
+// the window, so moving within the window does not shift the list.
+
+// scrollIndicators is the number of lines taken by the "↑ N more" and
+// "↓ N more" lines of a scrolled list.
+const scrollIndicators = 2
+
+// rowHeight is the number of lines each item takes.
+func (t TodoList) rowHeight() int {
+	if t.simpleMode {
+		return 2
+	}
+	return 1
+}
+
+// listHeight is the number of lines left for the list once the other
+// components are laid out, or zero when the terminal height is unknown.
+func (t TodoList) listHeight() int {
+	if t.height <= 0 {
+		return 0
+	}
+	above := renderComponents(t, aboveList)
+	below := renderComponents(t, belowList)
+	return max(t.height-len(above)-len(below), 2)
+}
+
+// itemRows is how many of count items fit in height lines, after the blank
+// separator and, when they do not all fit, the scroll indicators.
+func (t TodoList) itemRows(height, count int) int {
+	if height <= 0 || (height-1)/t.rowHeight() >= count {
+		return count
+	}
+	return max((height-1-scrollIndicators)/t.rowHeight(), 1)
+}
+
+// visibleRange returns the positions in visibleItems shown in height lines,
+// starting from scrollOffset but always including the selection and its
+// detail line.
+func (t TodoList) visibleRange(height int) (int, int) {
+	visible := t.visibleItems()
+	count := len(visible)
+	rows := t.itemRows(height-t.detailRows(), count)
+	selected := max(visiblePos(visible, t.selectedIndex), 0)
+
+	start := min(max(t.scrollOffset, selected-rows+1), selected)
+	start = min(max(start, 0), count-rows)
+	return start, start + rows
+}
+
+// scrollToSelection moves scrollOffset just far enough to show the cursor.
+func (t *TodoList) scrollToSelection() {
+	t.viewportHeight = t.listHeight()
+	t.scrollOffset, _ = t.visibleRange(t.viewportHeight)
+}
+
+// scrollIndicator renders the hidden-row count above or below the window,
+// or a blank line when nothing is hidden on that side.
+func scrollIndicator(arrow string, hidden int) string {
+	if hidden == 0 {
+		return ""
+	}
+	return dimStyle.Render(fmt.Sprintf("  %s %d more", arrow, hidden))
+}
+
+// pageCursor moves the selection by pages of visible rows, stopping at
+// either end rather than wrapping, and scrolls the window with it. It
+// reports whether the selection moved.
+func (t *TodoList) pageCursor(pages float64) bool {
+	visible := t.visibleItems()
+	if len(visible) == 0 {
+		return false
+	}
+	from := t.selectedIndex
+	rows := t.itemRows(t.viewportHeight, len(visible))
+	step := max(int(float64(rows)*pages), 1)
+	if pages < 0 {
+		step = min(int(float64(rows)*pages), -1)
+	}
+
+	t.moveCursorBy(step)
+	t.scrollOffset = min(max(t.scrollOffset+step, 0), max(len(visible)-rows, 0))
+	return t.selectedIndex != from
+}
+
+// moveCursorBy moves the selection step visible rows, stopping at either
+// end.
+func (t *TodoList) moveCursorBy(step int) {
+	visible := t.visibleItems()
+	if len(visible) == 0 {
+		return
+	}
+	pos := visiblePos(visible, t.selectedIndex)
+	pos = min(max(pos+step, 0), len(visible)-1)
+	t.selectedIndex = visible[pos]
+}
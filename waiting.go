@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Waiting items are open but blocked on someone else. They count towards
+// the total but are hidden from the active filter, and completing one
+// clears the flag.
+
+// waitingTag marks a new or edited item as waiting.
+const waitingTag = "@waiting"
+
+var waitingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Faint(true)
+
+// parseWaitingTag takes @waiting tokens out of text.
+func parseWaitingTag(text string) (string, bool) {
+	var waiting bool
+	title, _ := takeTokens(text, func(word string) (bool, error) {
+		if !strings.EqualFold(word, waitingTag) {
+			return false, nil
+		}
+		waiting = true
+		return true, nil
+	})
+	return title, waiting
+}
+
+// ToggleWaiting flips the waiting flag of the item at index. Marking a
+// completed item as waiting reopens it.
+func (t *TodoList) ToggleWaiting(index int) error {
+	if !t.isValidIndex(index) {
+		return &ValidationError{Operation: "waiting", Err: errors.New("invalid index")}
+	}
+	t.recordUndo()
+	now := time.Now()
+	t.items.update([]int{index}, func(item *TodoItem) {
+		item.Waiting = !item.Waiting
+		if item.Waiting {
+			item.setCompleted(false, now)
+		}
+	})
+	t.save()
+	return nil
+}